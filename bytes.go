@@ -0,0 +1,100 @@
+package jason
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// Attempts to typecast the current value into bytes by decoding a base64 string.
+// Both the standard and the URL-safe alphabet are accepted, with or without padding,
+// which covers JWT segments as well as most signatures.
+// Returns error if the current value is not a json string or not valid base64.
+// Example:
+//		signature, err := v.Bytes()
+func (v *Value) Bytes() ([]byte, error) {
+	s, err := v.String()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var encoding *base64.Encoding
+
+	switch urlSafe, padded := strings.ContainsAny(s, "-_"), strings.HasSuffix(s, "="); {
+	case urlSafe && padded:
+		encoding = base64.URLEncoding
+	case urlSafe:
+		encoding = base64.RawURLEncoding
+	case padded:
+		encoding = base64.StdEncoding
+	default:
+		encoding = base64.RawStdEncoding
+	}
+
+	return encoding.DecodeString(s)
+}
+
+// Attempts to typecast the current value into bytes by decoding a hex string.
+// Upper and lower case digits are accepted.
+// Returns error if the current value is not a json string or not valid hex.
+// Example:
+//		digest, err := v.HexBytes()
+func (v *Value) HexBytes() ([]byte, error) {
+	s, err := v.String()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hex.DecodeString(s)
+}
+
+// Gets the value at key path and attempts to typecast the value into bytes by decoding a base64 string.
+// Returns error if the value is not a json string or not valid base64.
+// Example:
+//		signature, err := GetBytes("webhook", "signature")
+func (v *Object) GetBytes(keys ...string) ([]byte, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return child.Bytes()
+}
+
+// Gets the value at key path and attempts to typecast the value into bytes by decoding a hex string.
+// Returns error if the value is not a json string or not valid hex.
+// Example:
+//		digest, err := GetHexBytes("file", "sha256")
+func (v *Object) GetHexBytes(keys ...string) ([]byte, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return child.HexBytes()
+}
+
+// Creates a string value holding b encoded as padded standard base64.
+// Example:
+//		v := jason.NewBase64Value([]byte("hello"))
+func NewBase64Value(b []byte) *Value {
+	return &Value{base64.StdEncoding.EncodeToString(b), true}
+}
+
+// Creates a string value holding b encoded as unpadded URL-safe base64, as used in JWTs.
+// Example:
+//		v := jason.NewBase64URLValue(payload)
+func NewBase64URLValue(b []byte) *Value {
+	return &Value{base64.RawURLEncoding.EncodeToString(b), true}
+}
+
+// Creates a string value holding b encoded as lower case hex.
+// Example:
+//		v := jason.NewHexValue(digest[:])
+func NewHexValue(b []byte) *Value {
+	return &Value{hex.EncodeToString(b), true}
+}
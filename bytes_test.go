@@ -0,0 +1,51 @@
+package jason
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBytes(t *testing.T) {
+	assert := NewAssert(t)
+
+	data := []byte{0xfb, 0xff, 0xbf, 0x01, 0x02}
+
+	o, err := NewObjectFromBytes([]byte(`{
+		"std": "+/+/AQI=",
+		"raw": "+/+/AQI",
+		"url": "-_-_AQI=",
+		"rawurl": "-_-_AQI",
+		"hex": "FBFFBF0102",
+		"invalid": "not base64!",
+		"number": 1
+	}`))
+	assert.True(err == nil, "failed to parse object")
+
+	for _, key := range []string{"std", "raw", "url", "rawurl"} {
+		b, err := o.GetBytes(key)
+		assert.True(err == nil && bytes.Equal(b, data), key+" should decode")
+	}
+
+	b, err := o.GetHexBytes("hex")
+	assert.True(err == nil && bytes.Equal(b, data), "upper case hex should decode")
+
+	_, err = o.GetBytes("invalid")
+	assert.True(err != nil, "invalid base64 should return an error")
+
+	_, err = o.GetBytes("number")
+	assert.True(err == ErrNotString, "numbers should return ErrNotString")
+
+	s, _ := NewBase64Value(data).String()
+	assert.True(s == "+/+/AQI=", "NewBase64Value should use padded standard base64")
+
+	s, _ = NewBase64URLValue(data).String()
+	assert.True(s == "-_-_AQI", "NewBase64URLValue should use unpadded URL-safe base64")
+
+	s, _ = NewHexValue(data).String()
+	assert.True(s == "fbffbf0102", "NewHexValue should use lower case hex")
+
+	for _, v := range []*Value{NewBase64Value(data), NewBase64URLValue(data)} {
+		b, err := v.Bytes()
+		assert.True(err == nil && bytes.Equal(b, data), "encoded values should decode again")
+	}
+}
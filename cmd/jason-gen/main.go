@@ -0,0 +1,105 @@
+// Command jason-gen generates Go struct definitions from sample JSON documents.
+//
+// Usage:
+//		jason-gen [-package name] [-type name] [-o file] [sample.json ...]
+//
+// Samples are read from the given files, or from standard input if there are none.
+// Standard input may contain several concatenated documents.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antonholmquist/jason"
+	"github.com/antonholmquist/jason/codegen"
+)
+
+func main() {
+	pkg := flag.String("package", "main", "package name of the generated source")
+	typeName := flag.String("type", "Root", "name of the generated root type")
+	output := flag.String("o", "", "write the source to this file instead of standard output")
+	flag.Parse()
+
+	samples, err := readSamples(flag.Args())
+	if err != nil {
+		fail(err)
+	}
+
+	src, err := codegen.Generate(codegen.Options{Package: *pkg, TypeName: *typeName}, samples...)
+	if err != nil {
+		fail(err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*output, src, 0644)
+	}
+
+	if err != nil {
+		fail(err)
+	}
+}
+
+func readSamples(files []string) ([]*jason.Value, error) {
+	if len(files) == 0 {
+		return readStream(os.Stdin, "stdin")
+	}
+
+	var samples []*jason.Value
+
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := readStream(f, file)
+		f.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		samples = append(samples, values...)
+	}
+
+	return samples, nil
+}
+
+// Reads all concatenated documents from r.
+// The decoder only splits the documents, and each one is parsed by jason.
+func readStream(r io.Reader, name string) ([]*jason.Value, error) {
+	var samples []*jason.Value
+
+	d := json.NewDecoder(r)
+
+	for {
+		var raw json.RawMessage
+		err := d.Decode(&raw)
+
+		if err == io.EOF {
+			return samples, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+
+		v, err := jason.NewValueFromBytes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+
+		samples = append(samples, v)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "jason-gen:", err)
+	os.Exit(1)
+}
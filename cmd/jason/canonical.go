@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/antonholmquist/jason"
+)
+
+// Marshals a value into the JSON Canonicalization Scheme (RFC 8785).
+// The output has no whitespace, object keys sorted by their UTF-16 code units,
+// minimal string escaping and numbers formatted like ECMAScript does.
+// Equal documents always produce identical bytes, which makes the output
+// suitable for signing and for comparing documents byte by byte.
+// Returns an error for numbers that are out of range for a float64.
+func marshalCanonical(v *jason.Value) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := writeCanonical(&buf, v.Interface())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, data interface{}) error {
+	switch data := data.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(data))
+	case json.Number:
+		f, err := data.Float64()
+
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("jason: number %s can not be canonicalized", data)
+		}
+
+		buf.WriteString(formatECMAScript(f))
+	case string:
+		writeCanonicalString(buf, data)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, element := range data {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeCanonical(buf, element); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+
+			if err := writeCanonical(buf, data[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("jason: unsupported type %T", data)
+	}
+
+	return nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// Compares two strings by their UTF-16 code units, as RFC 8785 requires.
+func lessUTF16(a, b string) bool {
+	for a != "" && b != "" {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+
+		if ra != rb {
+			ua := utf16Units(ra)
+			ub := utf16Units(rb)
+
+			if ua[0] != ub[0] {
+				return ua[0] < ub[0]
+			}
+
+			return ua[1] < ub[1]
+		}
+
+		a, b = a[sizeA:], b[sizeB:]
+	}
+
+	return len(a) < len(b)
+}
+
+func utf16Units(r rune) [2]rune {
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError || r2 != utf8.RuneError {
+		return [2]rune{r1, r2}
+	}
+
+	return [2]rune{r, 0}
+}
+
+// Formats f like ECMAScript's Number.prototype.toString.
+func formatECMAScript(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// Shortest digits that round trip, in the form d.ddde±x.
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exponent, _ := strings.Cut(s, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exponent)
+
+	k := len(digits)
+	n := e + 1
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+
+	result := digits[:1]
+	if k > 1 {
+		result += "." + digits[1:]
+	}
+
+	if n-1 >= 0 {
+		return sign + result + "e+" + strconv.Itoa(n-1)
+	}
+
+	return sign + result + "e" + strconv.Itoa(n-1)
+}
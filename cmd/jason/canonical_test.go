@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/antonholmquist/jason"
+)
+
+func TestMarshalCanonical(t *testing.T) {
+	v, err := jason.NewValueFromBytes([]byte(`{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000001, 1e-7, -0, 100, 1e21, 123456789012345680000],
+		"string": "€$\u000f\nA'B\"\\\\\"/<>",
+		"literals": [null, true, false],
+		"€": 1, "\r": 2, "😀": 3, "דּ": 4, "1": 5, "10": 6, "a": 7
+	}`))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	b, err := marshalCanonical(v)
+	if err != nil {
+		t.Fatalf("failed to canonicalize: %v", err)
+	}
+
+	expected := `{"\r":2,"1":5,"10":6,"a":7,"literals":[null,true,false],` +
+		`"numbers":[333333333.3333333,1e+30,4.5,0.002,0.000001,1e-7,0,100,1e+21,123456789012345680000],` +
+		`"string":"€$\u000f\nA'B\"\\\\\"/<>","€":1,"😀":3,"דּ":4}`
+
+	if string(b) != expected {
+		t.Errorf("unexpected canonical output:\n%s\nexpected:\n%s", b, expected)
+	}
+
+	huge, _ := jason.NewValue(json.Number("1e400"))
+	if _, err := marshalCanonical(huge); err == nil {
+		t.Errorf("expected error for number out of range")
+	}
+}
+
+func TestFormatECMAScript(t *testing.T) {
+	cases := map[float64]string{
+		1:                   "1",
+		-1.5:                "-1.5",
+		1e21:                "1e+21",
+		1e20:                "100000000000000000000",
+		5e-324:              "5e-324",
+		math.MaxFloat64:     "1.7976931348623157e+308",
+		0.30000000000000004: "0.30000000000000004",
+		-0.0000033:          "-0.0000033",
+		1.2345e-7:           "1.2345e-7",
+		9007199254740992:    "9007199254740992",
+	}
+
+	for f, expected := range cases {
+		if s := formatECMAScript(f); s != expected {
+			t.Errorf("formatECMAScript(%v) = %s, expected %s", f, s, expected)
+		}
+	}
+}
@@ -0,0 +1,562 @@
+// Command jason reads, queries, formats and validates JSON documents.
+//
+// Key paths given to get, keys and type are resolved exactly like the
+// Get<Type>(keys ...) methods of the jason package, so the results match
+// what Go services using jason see.
+//
+// Usage:
+//		jason get [-f file] [-r] key...
+//		jason get [-f file] [-r] -p pointer
+//		jason query [-f file] [-r] [-paths] jsonpath
+//		jason fmt [-compact | -canonical] [-indent string] [-width n] [-ascii] [file...]
+//		jason validate [-schema file] [file...]
+//		jason keys [-f file] [key...]
+//		jason type [-f file] [key...]
+//
+// Documents are read from the given files, or from standard input.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/antonholmquist/jason"
+	"github.com/antonholmquist/jason/internal/jsonpath"
+	"github.com/antonholmquist/jason/schema"
+)
+
+const usage = `usage: jason <command> [flags] [arguments]
+
+commands:
+  get       print the value at a key path or JSON Pointer
+  query     print the values selected by a JSONPath query
+  fmt       format documents (pretty, compact or canonical)
+  validate  check syntax, and optionally a JSON Schema
+  keys      print the keys of the object at a key path
+  type      print the type of the value at a key path
+
+Run 'jason <command> -h' for the flags of a command.
+`
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Runs the command line and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	c := &command{name: args[0], stdin: stdin, stdout: stdout, stderr: stderr}
+	c.flags = flag.NewFlagSet("jason "+c.name, flag.ContinueOnError)
+	c.flags.SetOutput(stderr)
+
+	var err error
+
+	switch c.name {
+	case "get":
+		err = c.get(args[1:])
+	case "query":
+		err = c.query(args[1:])
+	case "fmt":
+		err = c.format(args[1:])
+	case "validate":
+		err = c.validate(args[1:])
+	case "keys":
+		err = c.keys(args[1:])
+	case "type":
+		err = c.typeOf(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "jason: unknown command %q\n\n%s", c.name, usage)
+		return exitUsage
+	}
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, errSilent):
+		return exitFailure
+	}
+
+	fmt.Fprintf(stderr, "jason %s: %v\n", c.name, err)
+	return exitFailure
+}
+
+var (
+	errUsage  = errors.New("usage error")
+	errSilent = errors.New("failure already reported")
+)
+
+type command struct {
+	name   string
+	flags  *flag.FlagSet
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// Parses flags, converting flag errors into usage errors.
+func (c *command) parse(args []string) error {
+	err := c.flags.Parse(args)
+
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
+	}
+
+	return err
+}
+
+// Reads one document from file, or from standard input if file is empty or "-".
+func (c *command) read(file string) (*jason.Value, error) {
+	var b []byte
+	var err error
+
+	if file == "" || file == "-" {
+		file = "stdin"
+		b, err = io.ReadAll(c.stdin)
+	} else {
+		b, err = os.ReadFile(file)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// NewValueFromBytes stops after the first value, so check the whole input first.
+	var raw json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %s", file, describeSyntaxError(b, err))
+	}
+
+	return jason.NewValueFromBytes(b)
+}
+
+// Adds the line and column to syntax errors.
+func describeSyntaxError(b []byte, err error) string {
+	var syntaxErr *json.SyntaxError
+
+	if !errors.As(err, &syntaxErr) {
+		return err.Error()
+	}
+
+	// Offset counts the offending byte, so stop just before it.
+	end := syntaxErr.Offset - 1
+	if end < 0 {
+		end = 0
+	}
+
+	line, column := 1, 1
+	for _, c := range b[:end] {
+		if c == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return fmt.Sprintf("line %d, column %d: %v", line, column, err)
+}
+
+// Resolves a key path like Object.GetValue. An empty path returns the document itself.
+func lookup(v *jason.Value, keys []string) (*jason.Value, error) {
+	if len(keys) == 0 {
+		return v, nil
+	}
+
+	o, err := v.Object()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.GetValue(keys...)
+}
+
+// Resolves a JSON Pointer (RFC 6901). Object members are read like Object.GetValue,
+// and tokens that are decimal numbers without leading zeros index arrays.
+func lookupPointer(v *jason.Value, pointer string) (*jason.Value, error) {
+	if pointer == "" {
+		return v, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid json pointer %q", pointer)
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		for i := 0; i < len(token); i++ {
+			if token[i] == '~' && (i+1 == len(token) || (token[i+1] != '0' && token[i+1] != '1')) {
+				return nil, fmt.Errorf("invalid json pointer %q", pointer)
+			}
+		}
+
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		if array, err := v.Array(); err == nil {
+			index, err := strconv.Atoi(token)
+
+			if err != nil || index < 0 || strconv.Itoa(index) != token {
+				return nil, jason.ErrNotObject
+			}
+
+			if index >= len(array) {
+				return nil, jason.ErrIndexOutOfRange
+			}
+
+			v = array[index]
+			continue
+		}
+
+		o, err := v.Object()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if v, err = o.GetValue(token); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// Reports whether a flag was given on the command line, even with an empty value.
+func (c *command) isSet(name string) bool {
+	set := false
+
+	c.flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+// Prints a value on one line. Strings are printed without quotes if raw is set.
+func (c *command) print(v *jason.Value, raw bool) error {
+	if raw {
+		if s, err := v.String(); err == nil {
+			_, err := fmt.Fprintln(c.stdout, s)
+			return err
+		}
+	}
+
+	e := jason.NewEncoder(c.stdout)
+	e.SetEscapeHTML(false)
+	e.SetTrailingNewline(true)
+
+	return e.Encode(v)
+}
+
+func (c *command) get(args []string) error {
+	file := c.flags.String("f", "", "read the document from `file` instead of standard input")
+	raw := c.flags.Bool("r", false, "print strings without quotes")
+	pointer := c.flags.String("p", "", "resolve a JSON `pointer` instead of a key path")
+
+	if err := c.parse(args); err != nil {
+		return err
+	}
+
+	v, err := c.read(*file)
+	if err != nil {
+		return err
+	}
+
+	var result *jason.Value
+
+	// An empty pointer selects the whole document, so check whether -p was given instead of its value.
+	if c.isSet("p") {
+		if c.flags.NArg() > 0 {
+			return fmt.Errorf("key path and -p can not be combined")
+		}
+
+		result, err = lookupPointer(v, *pointer)
+	} else {
+		result, err = lookup(v, c.flags.Args())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.print(result, *raw)
+}
+
+func (c *command) query(args []string) error {
+	file := c.flags.String("f", "", "read the document from `file` instead of standard input")
+	raw := c.flags.Bool("r", false, "print strings without quotes")
+	paths := c.flags.Bool("paths", false, "print the path of each match before its value")
+
+	if err := c.parse(args); err != nil {
+		return err
+	}
+
+	if c.flags.NArg() != 1 {
+		fmt.Fprintln(c.stderr, "usage: jason query [-f file] [-r] [-paths] jsonpath")
+		return errUsage
+	}
+
+	q, err := jsonpath.Compile(c.flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	v, err := c.read(*file)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range q.Select(v.Interface()) {
+		if *paths {
+			fmt.Fprintf(c.stdout, "%s\t", jason.Path(node.Path))
+		}
+
+		selected, err := jason.NewValue(node.Data)
+		if err != nil {
+			return err
+		}
+
+		if err := c.print(selected, *raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *command) format(args []string) error {
+	compact := c.flags.Bool("compact", false, "write compact output without whitespace")
+	canonical := c.flags.Bool("canonical", false, "write canonical output (RFC 8785)")
+	indent := c.flags.String("indent", "  ", "indentation `string` for pretty output")
+	width := c.flags.Int("width", 0, "keep arrays of scalars on one line if it fits within `n` columns")
+	ascii := c.flags.Bool("ascii", false, "escape all non-ASCII characters")
+
+	if err := c.parse(args); err != nil {
+		return err
+	}
+
+	if *compact && *canonical {
+		fmt.Fprintln(c.stderr, "jason fmt: -compact and -canonical can not be combined")
+		return errUsage
+	}
+
+	return c.eachDocument(func(name string, v *jason.Value) error {
+		if *canonical {
+			b, err := marshalCanonical(v)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(c.stdout, "%s\n", b)
+			return err
+		}
+
+		e := jason.NewEncoder(c.stdout)
+		e.SetEscapeHTML(false)
+		e.SetASCII(*ascii)
+		e.SetTrailingNewline(true)
+
+		if !*compact {
+			e.SetIndent("", *indent)
+			e.SetMaxWidth(*width)
+		}
+
+		return e.Encode(v)
+	})
+}
+
+func (c *command) validate(args []string) error {
+	schemaFile := c.flags.String("schema", "", "also validate against the JSON Schema in `file`")
+
+	if err := c.parse(args); err != nil {
+		return err
+	}
+
+	var s *schema.Schema
+
+	if *schemaFile != "" {
+		v, err := c.read(*schemaFile)
+		if err != nil {
+			return err
+		}
+
+		s, err = schema.Compile(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	failed := false
+
+	err := c.eachDocument(func(name string, v *jason.Value) error {
+		if s != nil {
+			if err := s.Validate(v); err != nil {
+				var verr *schema.ValidationError
+
+				if !errors.As(err, &verr) {
+					return err
+				}
+
+				for _, violation := range verr.Violations {
+					fmt.Fprintf(c.stdout, "%s: %s\n", name, violation)
+				}
+
+				failed = true
+				return nil
+			}
+		}
+
+		fmt.Fprintf(c.stdout, "%s: ok\n", name)
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if failed {
+		return errSilent
+	}
+
+	return nil
+}
+
+func (c *command) keys(args []string) error {
+	file := c.flags.String("f", "", "read the document from `file` instead of standard input")
+
+	if err := c.parse(args); err != nil {
+		return err
+	}
+
+	v, err := c.read(*file)
+	if err != nil {
+		return err
+	}
+
+	result, err := lookup(v, c.flags.Args())
+	if err != nil {
+		return err
+	}
+
+	o, err := result.Object()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(o.Map()))
+	for key := range o.Map() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintln(c.stdout, key)
+	}
+
+	return nil
+}
+
+func (c *command) typeOf(args []string) error {
+	file := c.flags.String("f", "", "read the document from `file` instead of standard input")
+
+	if err := c.parse(args); err != nil {
+		return err
+	}
+
+	v, err := c.read(*file)
+	if err != nil {
+		return err
+	}
+
+	result, err := lookup(v, c.flags.Args())
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(c.stdout, typeName(result))
+	return err
+}
+
+// Calls fn for the document in each file argument, or for standard input.
+// Syntax errors are reported and processing continues with the next file.
+func (c *command) eachDocument(fn func(name string, v *jason.Value) error) error {
+	files := c.flags.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	failed := false
+
+	for _, file := range files {
+		name := file
+		if name == "-" {
+			name = "stdin"
+		}
+
+		v, err := c.read(file)
+		if err != nil {
+			fmt.Fprintln(c.stderr, err)
+			failed = true
+			continue
+		}
+
+		if err := fn(name, v); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	if failed {
+		return errSilent
+	}
+
+	return nil
+}
+
+// Returns the JSON type of v. Numbers are reported as integer if GetInt64 accepts them.
+func typeName(v *jason.Value) string {
+	switch v.Interface().(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "unknown"
+}
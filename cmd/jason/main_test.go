@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antonholmquist/jason"
+)
+
+const document = `{"name": "anton", "age": 29, "tags": ["a", "b"], "address": {"city": "Stockholm", "zip": "<111>"}, "ratio": 0.5}`
+
+func runCommand(t *testing.T, stdin string, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return stdout.String(), stderr.String(), code
+}
+
+func TestGet(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+		code     int
+	}{
+		{[]string{"get", "name"}, "\"anton\"\n", 0},
+		{[]string{"get", "-r", "address", "city"}, "Stockholm\n", 0},
+		{[]string{"get", "address"}, "{\"city\":\"Stockholm\",\"zip\":\"<111>\"}\n", 0},
+		{[]string{"get", "-p", "/tags/1"}, "\"b\"\n", 0},
+		{[]string{"get", "-r", "-p", ""}, "{\"address\":{\"city\":\"Stockholm\",\"zip\":\"<111>\"},\"age\":29,\"name\":\"anton\",\"ratio\":0.5,\"tags\":[\"a\",\"b\"]}\n", 0},
+		{[]string{"get", "-p", "", "name"}, "", 1},
+		{[]string{"get", "missing"}, "", 1},
+		{[]string{"get", "tags", "0"}, "", 1},
+	}
+
+	for _, c := range cases {
+		stdout, stderr, code := runCommand(t, document, c.args...)
+
+		if stdout != c.expected || code != c.code {
+			t.Errorf("%v: got %q (exit %d, stderr %q), expected %q (exit %d)", c.args, stdout, code, stderr, c.expected, c.code)
+		}
+	}
+}
+
+func TestLookupPointer(t *testing.T) {
+	v, err := jason.NewValueFromBytes([]byte(`{"friends": [{"name": "walter"}, {"name": "jesse"}], "a/b": {"~": 1}, "0": "zero", "": "empty"}`))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	found := map[string]string{
+		"/friends/1/name": `"jesse"`,
+		"/a~1b/~0":        `1`,
+		"/0":              `"zero"`,
+		"/":               `"empty"`,
+	}
+
+	for pointer, expected := range found {
+		result, err := lookupPointer(v, pointer)
+		if err != nil {
+			t.Errorf("%q: %v", pointer, err)
+			continue
+		}
+
+		if b, _ := result.Marshal(); string(b) != expected {
+			t.Errorf("%q: got %s, expected %s", pointer, b, expected)
+		}
+	}
+
+	if root, err := lookupPointer(v, ""); err != nil || root != v {
+		t.Errorf("the empty pointer should return the document")
+	}
+
+	errs := map[string]error{
+		"/friends/2":  jason.ErrIndexOutOfRange,
+		"/friends/01": jason.ErrNotObject,
+		"/missing":    jason.KeyNotFoundError{Key: "missing"},
+	}
+
+	for pointer, expected := range errs {
+		if _, err := lookupPointer(v, pointer); err != expected {
+			t.Errorf("%q: got error %v, expected %v", pointer, err, expected)
+		}
+	}
+
+	for _, invalid := range []string{"friends", "/a~", "/a~2"} {
+		if _, err := lookupPointer(v, invalid); err == nil || !strings.Contains(err.Error(), "invalid json pointer") {
+			t.Errorf("%q: expected an invalid pointer error, got %v", invalid, err)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	stdout, _, code := runCommand(t, document, "query", "-paths", "-r", "$.tags[*]")
+
+	if code != 0 || stdout != "$.tags[0]\ta\n$.tags[1]\tb\n" {
+		t.Errorf("unexpected query output %q (exit %d)", stdout, code)
+	}
+
+	_, stderr, code := runCommand(t, document, "query", "$[")
+	if code != 1 || !strings.Contains(stderr, "invalid jsonpath") {
+		t.Errorf("expected jsonpath error, got %q (exit %d)", stderr, code)
+	}
+}
+
+func TestFmt(t *testing.T) {
+	input := `{"b": [1, 2], "a": "é"}`
+
+	stdout, _, _ := runCommand(t, input, "fmt")
+	if stdout != "{\n  \"a\": \"é\",\n  \"b\": [\n    1,\n    2\n  ]\n}\n" {
+		t.Errorf("unexpected pretty output %q", stdout)
+	}
+
+	stdout, _, _ = runCommand(t, input, "fmt", "-width", "80")
+	if stdout != "{\n  \"a\": \"é\",\n  \"b\": [1, 2]\n}\n" {
+		t.Errorf("unexpected width output %q", stdout)
+	}
+
+	stdout, _, _ = runCommand(t, input, "fmt", "-compact", "-ascii")
+	if stdout != "{\"a\":\"\\u00e9\",\"b\":[1,2]}\n" {
+		t.Errorf("unexpected compact output %q", stdout)
+	}
+
+	stdout, _, _ = runCommand(t, `{"n": 1.50, "m": 1e2}`, "fmt", "-canonical")
+	if stdout != "{\"m\":100,\"n\":1.5}\n" {
+		t.Errorf("unexpected canonical output %q", stdout)
+	}
+
+	_, _, code := runCommand(t, input, "fmt", "-compact", "-canonical")
+	if code != 2 {
+		t.Errorf("expected usage error, got exit %d", code)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+
+	schemaFile := filepath.Join(dir, "schema.json")
+	os.WriteFile(schemaFile, []byte(`{"required": ["name", "email"], "properties": {"age": {"type": "string"}}}`), 0644)
+
+	stdout, _, code := runCommand(t, document, "validate")
+	if code != 0 || stdout != "stdin: ok\n" {
+		t.Errorf("unexpected output %q (exit %d)", stdout, code)
+	}
+
+	_, stderr, code := runCommand(t, "{\n  \"a\": 1,\n}", "validate")
+	if code != 1 || !strings.Contains(stderr, "stdin: line 3, column 1") {
+		t.Errorf("expected syntax error with position, got %q (exit %d)", stderr, code)
+	}
+
+	_, _, code = runCommand(t, `{} {}`, "validate")
+	if code != 1 {
+		t.Errorf("trailing data should be a syntax error, got exit %d", code)
+	}
+
+	stdout, _, code = runCommand(t, document, "validate", "-schema", schemaFile)
+	expected := "stdin: /: missing required property \"email\" (/required)\n" +
+		"stdin: /age: expected string, got number (/properties/age/type)\n"
+	if code != 1 || stdout != expected {
+		t.Errorf("unexpected schema output %q (exit %d)", stdout, code)
+	}
+}
+
+func TestKeysAndType(t *testing.T) {
+	stdout, _, _ := runCommand(t, document, "keys")
+	if stdout != "address\nage\nname\nratio\ntags\n" {
+		t.Errorf("unexpected keys %q", stdout)
+	}
+
+	stdout, _, _ = runCommand(t, document, "keys", "address")
+	if stdout != "city\nzip\n" {
+		t.Errorf("unexpected nested keys %q", stdout)
+	}
+
+	for key, expected := range map[string]string{"name": "string", "age": "integer", "ratio": "number", "tags": "array", "address": "object"} {
+		stdout, _, _ := runCommand(t, document, "type", key)
+		if stdout != expected+"\n" {
+			t.Errorf("type of %s: got %q, expected %q", key, stdout, expected)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	if _, _, code := runCommand(t, ""); code != 2 {
+		t.Errorf("expected usage exit code without arguments, got %d", code)
+	}
+
+	if _, _, code := runCommand(t, "", "unknown"); code != 2 {
+		t.Errorf("expected usage exit code for unknown command, got %d", code)
+	}
+
+	if _, _, code := runCommand(t, "", "get", "-x"); code != 2 {
+		t.Errorf("expected usage exit code for unknown flag, got %d", code)
+	}
+}
@@ -0,0 +1,363 @@
+// Package codegen generates Go struct definitions from sample JSON documents.
+//
+// The shapes of all samples are merged, and every type decision uses the jason
+// accessors: a number becomes int64 only if GetInt64 accepts it in every sample,
+// float64 if GetFloat64 does, and json.Number otherwise.
+//
+// Keys that are missing from some samples or null in some samples become pointer
+// fields, except for slices and interface{} which already have a nil value.
+// Fields for missing keys are also tagged omitempty.
+//
+// Generate code for two samples:
+//		src, err := codegen.Generate(codegen.Options{Package: "api", TypeName: "User"}, first, second)
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/antonholmquist/jason"
+)
+
+// Options control the generated source.
+type Options struct {
+	Package  string // Package name, "main" if empty
+	TypeName string // Name of the root type, "Root" if empty
+}
+
+// Error values returned when generating code
+var (
+	ErrNoSamples = errors.New("no samples")
+)
+
+// Generates Go source declaring a type that can hold every sample.
+// The source is formatted with gofmt.
+func Generate(opts Options, samples ...*jason.Value) ([]byte, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoSamples
+	}
+
+	if opts.Package == "" {
+		opts.Package = "main"
+	}
+
+	if opts.TypeName == "" {
+		opts.TypeName = "Root"
+	}
+
+	root := &shape{}
+	for _, sample := range samples {
+		root.add(sample)
+	}
+
+	g := &generator{names: make(map[string]bool)}
+	g.names[opts.TypeName] = true
+
+	if root.kind() == "object" {
+		g.declare(opts.TypeName, root)
+	} else {
+		g.types = append(g.types, declaration{name: opts.TypeName})
+		g.types[0].source = fmt.Sprintf("type %s %s\n", opts.TypeName, g.typeOf(opts.TypeName+"Item", root))
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by jason-gen from %d sample(s). DO NOT EDIT.\n\n", len(samples))
+	fmt.Fprintf(&buf, "package %s\n\n", opts.Package)
+
+	if g.usesNumber {
+		buf.WriteString("import \"encoding/json\"\n\n")
+	}
+
+	for _, t := range g.types {
+		buf.WriteString(t.source)
+		buf.WriteString("\n")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// shape accumulates what has been observed at one location across all samples.
+type shape struct {
+	present int
+	nulls   int
+
+	booleans int
+	strings  int
+	numbers  int
+	arrays   int
+	objects  int
+
+	notInt64   bool
+	notFloat64 bool
+
+	properties map[string]*shape
+	items      *shape
+}
+
+func (s *shape) add(v *jason.Value) {
+	s.present++
+
+	switch v.Interface().(type) {
+	case nil:
+		s.nulls++
+	case bool:
+		s.booleans++
+	case string:
+		s.strings++
+	case json.Number:
+		s.numbers++
+
+		if _, err := v.Int64(); err != nil {
+			s.notInt64 = true
+		}
+
+		if _, err := v.Float64(); err != nil {
+			s.notFloat64 = true
+		}
+	case []interface{}:
+		s.arrays++
+
+		array, _ := v.Array()
+		for _, element := range array {
+			if s.items == nil {
+				s.items = &shape{}
+			}
+			s.items.add(element)
+		}
+	case map[string]interface{}:
+		s.objects++
+
+		if s.properties == nil {
+			s.properties = make(map[string]*shape)
+		}
+
+		obj, _ := v.Object()
+		for key, child := range obj.Map() {
+			property, ok := s.properties[key]
+			if !ok {
+				property = &shape{}
+				s.properties[key] = property
+			}
+			property.add(child)
+		}
+	}
+}
+
+// Returns the single kind of non-null value observed, "" if there were none
+// and "mixed" if there were several.
+func (s *shape) kind() string {
+	kind := ""
+
+	for _, k := range []struct {
+		name  string
+		count int
+	}{
+		{"boolean", s.booleans},
+		{"string", s.strings},
+		{"number", s.numbers},
+		{"array", s.arrays},
+		{"object", s.objects},
+	} {
+		if k.count == 0 {
+			continue
+		}
+
+		if kind != "" {
+			return "mixed"
+		}
+
+		kind = k.name
+	}
+
+	return kind
+}
+
+type declaration struct {
+	name   string
+	source string
+}
+
+type generator struct {
+	types      []declaration
+	names      map[string]bool
+	usesNumber bool
+}
+
+// Returns the Go type for values of the shape. Objects are declared as a new
+// struct type, named after hint.
+func (g *generator) typeOf(hint string, s *shape) string {
+	switch s.kind() {
+	case "boolean":
+		return "bool"
+	case "string":
+		return "string"
+	case "number":
+		if !s.notInt64 {
+			return "int64"
+		}
+
+		if !s.notFloat64 {
+			return "float64"
+		}
+
+		g.usesNumber = true
+		return "json.Number"
+	case "array":
+		if s.items == nil {
+			return "[]interface{}"
+		}
+
+		element := g.typeOf(hint, s.items)
+		if s.items.nulls > 0 && isPointable(element) {
+			element = "*" + element
+		}
+
+		return "[]" + element
+	case "object":
+		name := g.uniqueName(hint)
+		g.declare(name, s)
+		return name
+	}
+
+	return "interface{}"
+}
+
+// Declares a struct type for an object shape.
+func (g *generator) declare(name string, s *shape) {
+	index := len(g.types)
+	g.types = append(g.types, declaration{name: name})
+
+	keys := make([]string, 0, len(s.properties))
+	for key := range s.properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+
+	fields := make(map[string]bool)
+
+	for _, key := range keys {
+		property := s.properties[key]
+
+		field := fieldName(key)
+		for i := 2; fields[field]; i++ {
+			field = fieldName(key) + strconv.Itoa(i)
+		}
+		fields[field] = true
+
+		optional := property.present < s.objects
+		t := g.typeOf(name+field, property)
+
+		if (optional || property.nulls > 0) && isPointable(t) {
+			t = "*" + t
+		}
+
+		tag := key
+		if optional {
+			tag += ",omitempty"
+		}
+
+		fmt.Fprintf(&b, "\t%s %s %s\n", field, t, tagLiteral("json:"+strconv.Quote(tag)))
+	}
+
+	b.WriteString("}\n")
+
+	g.types[index].source = b.String()
+}
+
+// Returns the literal of a struct tag, which is a raw string unless the tag contains a backtick.
+func tagLiteral(tag string) string {
+	if strings.ContainsRune(tag, '`') {
+		return strconv.Quote(tag)
+	}
+
+	return "`" + tag + "`"
+}
+
+func (g *generator) uniqueName(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+
+	g.names[unique] = true
+
+	return unique
+}
+
+// Reports whether a nil pointer to the type is needed to represent a missing or null value.
+func isPointable(t string) bool {
+	return !strings.HasPrefix(t, "[]") && t != "interface{}"
+}
+
+// Initialisms written in upper case in field names, following Go conventions.
+var initialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true,
+	"UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// Converts a JSON key like "user_id" or "createdAt" into an exported Go identifier like "UserID".
+func fieldName(key string) string {
+	var words []string
+	var word []rune
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(word) > 0 && (unicode.IsLower(word[len(word)-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		upper := strings.ToUpper(w)
+
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+
+		r := []rune(strings.ToLower(w))
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	name := b.String()
+
+	if name == "" {
+		return "Field"
+	}
+
+	if r := []rune(name)[0]; !unicode.IsLetter(r) {
+		name = "X" + name
+	}
+
+	return name
+}
@@ -0,0 +1,116 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/antonholmquist/jason"
+)
+
+func mustValue(t *testing.T, s string) *jason.Value {
+	v, err := jason.NewValueFromBytes([]byte(s))
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", s, err)
+	}
+
+	return v
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate(Options{Package: "api", TypeName: "User"},
+		mustValue(t, `{"user_id": 1, "name": "anton", "score": 1.5, "balance": 1e400, "tags": ["a"], "address": {"city": "Stockholm"}, "nickname": null, "createdAt": "2010"}`),
+		mustValue(t, `{"user_id": 2, "name": "walter", "score": 2, "balance": 1, "tags": [], "nickname": "heisenberg", "createdAt": "2011", "http_url": "x"}`),
+	)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	expected := "// Code generated by jason-gen from 2 sample(s). DO NOT EDIT.\n" +
+		"\n" +
+		"package api\n" +
+		"\n" +
+		"import \"encoding/json\"\n" +
+		"\n" +
+		"type User struct {\n" +
+		"\tAddress   *UserAddress `json:\"address,omitempty\"`\n" +
+		"\tBalance   json.Number  `json:\"balance\"`\n" +
+		"\tCreatedAt string       `json:\"createdAt\"`\n" +
+		"\tHTTPURL   *string      `json:\"http_url,omitempty\"`\n" +
+		"\tName      string       `json:\"name\"`\n" +
+		"\tNickname  *string      `json:\"nickname\"`\n" +
+		"\tScore     float64      `json:\"score\"`\n" +
+		"\tTags      []string     `json:\"tags\"`\n" +
+		"\tUserID    int64        `json:\"user_id\"`\n" +
+		"}\n" +
+		"\n" +
+		"type UserAddress struct {\n" +
+		"\tCity string `json:\"city\"`\n" +
+		"}\n"
+
+	if string(src) != expected {
+		t.Errorf("unexpected source:\n%s", src)
+	}
+}
+
+func TestGenerateArray(t *testing.T) {
+	src, err := Generate(Options{TypeName: "Items"}, mustValue(t, `[{"id": 1, "value": "a"}, {"id": 2}, null]`))
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	expected := "// Code generated by jason-gen from 1 sample(s). DO NOT EDIT.\n" +
+		"\n" +
+		"package main\n" +
+		"\n" +
+		"type Items []*ItemsItem\n" +
+		"\n" +
+		"type ItemsItem struct {\n" +
+		"\tID    int64   `json:\"id\"`\n" +
+		"\tValue *string `json:\"value,omitempty\"`\n" +
+		"}\n"
+
+	if string(src) != expected {
+		t.Errorf("unexpected source:\n%s", src)
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	cases := map[string]string{
+		"name":        "Name",
+		"user_id":     "UserID",
+		"createdAt":   "CreatedAt",
+		"HTTPServer":  "HTTPServer",
+		"api-key":     "APIKey",
+		"2fa_enabled": "X2faEnabled",
+		"$":           "Field",
+	}
+
+	for key, expected := range cases {
+		if name := fieldName(key); name != expected {
+			t.Errorf("fieldName(%q) = %q, expected %q", key, name, expected)
+		}
+	}
+
+	if _, err := Generate(Options{}); err != ErrNoSamples {
+		t.Errorf("expected ErrNoSamples, got %v", err)
+	}
+}
+
+func TestGenerateTagQuoting(t *testing.T) {
+	src, err := Generate(Options{}, mustValue(t, "{\"a`b\": 1, \"c\\\"d\": 2}"))
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	expected := "// Code generated by jason-gen from 1 sample(s). DO NOT EDIT.\n" +
+		"\n" +
+		"package main\n" +
+		"\n" +
+		"type Root struct {\n" +
+		"\tAB int64 \"json:\\\"a`b\\\"\"\n" +
+		"\tCD int64 `json:\"c\\\"d\"`\n" +
+		"}\n"
+
+	if string(src) != expected {
+		t.Errorf("unexpected source:\n%s", src)
+	}
+}
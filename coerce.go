@@ -0,0 +1,197 @@
+package jason
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Coercion describes a value that was converted into another JSON type.
+type Coercion struct {
+	Path  Path   // Key path of the value
+	From  string // JSON type of the value, like "string"
+	To    string // JSON type it was converted into, like "number", or "integer" for numbers like 10.0
+	Value *Value // The original value
+}
+
+// Coercer reads values from an object like the Get<Type> methods, but converts
+// between strings, numbers and booleans instead of returning an error:
+//
+//   - Numbers are read from strings holding a JSON number, like "42", and from
+//     booleans, where true is 1 and false is 0.
+//   - Booleans are read from strings accepted by strconv.ParseBool, like "true" or "1",
+//     and from the numbers 0 and 1.
+//   - Strings are read from numbers, written as they appear in the document, and from booleans.
+//
+// Null, arrays and objects are never coerced.
+type Coercer struct {
+	object *Object
+	hook   func(Coercion)
+}
+
+// Returns a Coercer that reads from the object with lenient type conversion.
+// The Get<Type> methods of the object itself stay strict.
+// Example:
+//		count, err := o.Coerce().GetInt64("count")
+func (v *Object) Coerce() *Coercer {
+	return &Coercer{object: v}
+}
+
+// Sets a function that is called every time a value is converted into another type,
+// for example to log how often an API violates its contract. Returns the coercer.
+// Example:
+//		c := o.Coerce().OnCoerce(func(c jason.Coercion) { log.Printf("coerced %s to %s at %s", c.From, c.To, c.Path) })
+func (c *Coercer) OnCoerce(hook func(Coercion)) *Coercer {
+	c.hook = hook
+	return c
+}
+
+// Gets the value at key path and attempts to coerce it into a string.
+// Returns error if the value is null, an array or an object.
+func (c *Coercer) GetString(keys ...string) (string, error) {
+	child, err := c.object.getPath(keys)
+
+	if err != nil {
+		return "", err
+	}
+
+	switch data := child.data.(type) {
+	case string:
+		return data, nil
+	case json.Number:
+		c.report(keys, child, "string")
+		return string(data), nil
+	case bool:
+		c.report(keys, child, "string")
+		return strconv.FormatBool(data), nil
+	}
+
+	return "", ErrNotString
+}
+
+// Gets the value at key path and attempts to coerce it into a number.
+// Returns error if the value is not a number, a string holding a number or a boolean.
+func (c *Coercer) GetNumber(keys ...string) (json.Number, error) {
+	child, err := c.object.getPath(keys)
+
+	if err != nil {
+		return "", err
+	}
+
+	switch data := child.data.(type) {
+	case json.Number:
+		return data, nil
+	case string:
+		if !isValidNumber(data) {
+			return "", ErrNotNumber
+		}
+
+		c.report(keys, child, "number")
+		return json.Number(data), nil
+	case bool:
+		c.report(keys, child, "number")
+
+		if data {
+			return "1", nil
+		}
+
+		return "0", nil
+	}
+
+	return "", ErrNotNumber
+}
+
+// Gets the value at key path and attempts to coerce it into a float64.
+// Returns error like GetNumber.
+func (c *Coercer) GetFloat64(keys ...string) (float64, error) {
+	n, err := c.GetNumber(keys...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return n.Float64()
+}
+
+// Gets the value at key path and attempts to coerce it into an int64.
+// Integral numbers like "1e3" and 10.0 are accepted, and numbers that aren't
+// integer literals are reported as coerced into "integer".
+// Returns error like GetNumber, or an *IntegerError if the number is not an integer or overflows.
+func (c *Coercer) GetInt64(keys ...string) (int64, error) {
+	n, err := c.GetNumber(keys...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	v := &Value{n, true}
+	i, err := v.Integer(IntegralNumbers, 64)
+
+	if err != nil {
+		return 0, withPath(err, keyPath(keys))
+	}
+
+	// Strings and booleans were already reported by GetNumber.
+	if _, err := v.Integer(StrictIntegers, 64); err != nil {
+		if child, _ := c.object.getPath(keys); child != nil {
+			if _, ok := child.data.(json.Number); ok {
+				c.report(keys, child, "integer")
+			}
+		}
+	}
+
+	return i, nil
+}
+
+// Gets the value at key path and attempts to coerce it into a bool.
+// Returns error if the value is not a boolean, a string accepted by strconv.ParseBool or the number 0 or 1.
+func (c *Coercer) GetBoolean(keys ...string) (bool, error) {
+	child, err := c.object.getPath(keys)
+
+	if err != nil {
+		return false, err
+	}
+
+	switch data := child.data.(type) {
+	case bool:
+		return data, nil
+	case string:
+		b, err := strconv.ParseBool(data)
+
+		if err != nil {
+			return false, ErrNotBool
+		}
+
+		c.report(keys, child, "boolean")
+		return b, nil
+	case json.Number:
+		d := parseDecimal(data)
+
+		switch {
+		case d.valid && d.digits == "":
+			c.report(keys, child, "boolean")
+			return false, nil
+		case d.valid && d.digits == "1" && d.exp == 1 && !d.neg:
+			c.report(keys, child, "boolean")
+			return true, nil
+		}
+	}
+
+	return false, ErrNotBool
+}
+
+// Calls the hook, if any, for a value converted into another type.
+func (c *Coercer) report(keys []string, v *Value, to string) {
+	if c.hook == nil {
+		return
+	}
+
+	from := "string"
+	switch v.data.(type) {
+	case json.Number:
+		from = "number"
+	case bool:
+		from = "boolean"
+	}
+
+	c.hook(Coercion{Path: keyPath(keys), From: from, To: to, Value: v})
+}
@@ -0,0 +1,71 @@
+package jason
+
+import (
+	"testing"
+)
+
+func TestCoerce(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{"count": "42", "exp": "1e3", "ratio": "0.5", "flag": "true", "one": 1, "zero": 0.0, "two": 2, "real": 7, "whole": 10.0, "yes": true, "bad": "forty", "nothing": null, "nested": {"id": 17}}`))
+	assert.True(err == nil, "failed to parse object")
+
+	var coercions []Coercion
+	c := o.Coerce().OnCoerce(func(c Coercion) {
+		coercions = append(coercions, c)
+	})
+
+	count, err := c.GetInt64("count")
+	assert.True(err == nil && count == 42, "strings holding numbers should coerce into int64")
+
+	exp, err := c.GetInt64("exp")
+	assert.True(err == nil && exp == 1000, "exponents in strings should coerce into int64")
+
+	ratio, err := c.GetFloat64("ratio")
+	assert.True(err == nil && ratio == 0.5, "strings holding numbers should coerce into float64")
+
+	yes, err := c.GetInt64("yes")
+	assert.True(err == nil && yes == 1, "true should coerce into 1")
+
+	flag, err := c.GetBoolean("flag")
+	assert.True(err == nil && flag, "\"true\" should coerce into true")
+
+	one, err := c.GetBoolean("one")
+	assert.True(err == nil && one, "1 should coerce into true")
+
+	zero, err := c.GetBoolean("zero")
+	assert.True(err == nil && !zero, "0.0 should coerce into false")
+
+	_, err = c.GetBoolean("two")
+	assert.True(err == ErrNotBool, "2 should not coerce into a boolean")
+
+	id, err := c.GetString("nested", "id")
+	assert.True(err == nil && id == "17", "numbers should coerce into strings")
+
+	assert.True(len(coercions) == 8, "every conversion should be reported")
+	assert.True(coercions[0].Path.String() == "$.count" && coercions[0].From == "string" && coercions[0].To == "number", "coercion should describe the conversion")
+	assert.True(coercions[7].Path.String() == "$.nested.id" && coercions[7].From == "number" && coercions[7].To == "string", "nested coercion should describe the path")
+
+	coercions = nil
+
+	real, err := c.GetInt64("real")
+	assert.True(err == nil && real == 7 && len(coercions) == 0, "values of the right type should not be reported")
+
+	whole, err := c.GetInt64("whole")
+	assert.True(err == nil && whole == 10 && len(coercions) == 1, "integral numbers should coerce into int64")
+	assert.True(coercions[0].Path.String() == "$.whole" && coercions[0].From == "number" && coercions[0].To == "integer", "integral numbers should be reported")
+
+	coercions = nil
+
+	exp, err = c.GetInt64("exp")
+	assert.True(err == nil && exp == 1000 && len(coercions) == 1 && coercions[0].To == "number", "strings should only be reported once")
+
+	_, err = c.GetNumber("bad")
+	assert.True(err == ErrNotNumber, "strings not holding numbers should not coerce")
+
+	_, err = c.GetString("nothing")
+	assert.True(err == ErrNotString, "null should not coerce into a string")
+
+	_, err = o.GetInt64("count")
+	assert.True(err != nil, "the object itself should stay strict")
+}
@@ -0,0 +1,626 @@
+package jason
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Document is a json document that keeps its original text, for tools that rewrite config files.
+// Set and Delete only change the bytes of the edited members, so comments, whitespace
+// and the order of keys are kept. New members are formatted like their siblings.
+// Example:
+//		doc, err := jason.ParseDocument(b, jason.JSONC)
+//		err = doc.Set(jason.Path{"editor", "fontSize"}, size)
+//		err = os.WriteFile(name, doc.Bytes(), 0644)
+type Document struct {
+	src     []byte
+	options parseOptions
+	root    *cstNode
+}
+
+// A node of the concrete syntax tree, with its location in the source.
+type cstNode struct {
+	kind       byte // '{', '[', or 0 for scalars
+	start, end int
+	members    []cstMember // Members of objects and elements of arrays
+}
+
+type cstMember struct {
+	key    string // The key of object members
+	start  int    // Start of the key, or of the element
+	keyEnd int
+	value  *cstNode
+	comma  int // Position of the comma after the member, -1 if there is none
+}
+
+// Parses a document for editing. Pass JSONC or JSON5 to allow comments and other relaxed syntax.
+// Returns an error if b isn't valid in the syntax.
+func ParseDocument(b []byte, options ...ParseOption) (*Document, error) {
+	d := &Document{options: parseOptionsOf(options)}
+
+	if err := d.parse(bytes.Clone(b)); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Parses b and makes it the document text.
+func (d *Document) parse(b []byte) error {
+	if d.options.syntax == StrictJSON {
+		if err := json.Unmarshal(b, new(json.RawMessage)); err != nil {
+			return err
+		}
+	}
+
+	p := &relaxedParser{src: string(b), json5: d.options.syntax == JSON5}
+	root, err := p.parseNode(0)
+
+	if err != nil {
+		return err
+	}
+
+	if err := p.skipSpace(); err != nil {
+		return err
+	}
+
+	if p.pos < len(p.src) {
+		return p.errorf("unexpected data after the value")
+	}
+
+	d.src, d.root = b, root
+
+	return nil
+}
+
+// Returns the text of the document, including all edits.
+func (d *Document) Bytes() []byte {
+	return bytes.Clone(d.src)
+}
+
+// Returns the value of the document, without its formatting.
+func (d *Document) Value() (*Value, error) {
+	return NewValueFromBytes(d.src, d.options.syntax)
+}
+
+// Sets the value at path, replacing an existing value or adding a member.
+// Missing objects along the path are created, and an array index equal to the
+// length of the array appends. Other values in the document are left as they are.
+// Example:
+//		err := doc.Set(jason.Path{"plugins", 2}, plugin)
+func (d *Document) Set(path Path, v *Value) error {
+	if len(path) == 0 {
+		text, err := d.encode(v.data, "", bytes.IndexByte(d.src, '\n') >= 0, d.separator(d.root))
+
+		if err != nil {
+			return err
+		}
+
+		return d.splice(d.root.start, d.root.end, text)
+	}
+
+	node := d.root
+
+	for i, element := range path {
+		j, err := node.member(element)
+
+		if err != nil {
+			return err
+		}
+
+		key, index, _ := pathElement(element)
+		last := i == len(path)-1
+
+		if j < 0 {
+			if node.kind == '[' {
+				if !last || index != len(node.members) {
+					return ErrIndexOutOfRange
+				}
+
+				return d.insert(node, nil, v.data)
+			}
+
+			// Create the objects for the rest of the path.
+			data := v.data
+
+			for k := len(path) - 1; k > i; k-- {
+				if _, ok := path[k].(int); ok {
+					return ErrIndexOutOfRange
+				}
+
+				rest, _, err := pathElement(path[k])
+
+				if err != nil {
+					return err
+				}
+
+				data = map[string]interface{}{rest: data}
+			}
+
+			return d.insert(node, &key, data)
+		}
+
+		if last {
+			value := node.members[j].value
+			text, err := d.encode(v.data, d.lineIndent(node.members[j].start), node.isMultiline(d.src), d.separator(node))
+
+			if err != nil {
+				return err
+			}
+
+			return d.splice(value.start, value.end, text)
+		}
+
+		node = node.members[j].value
+	}
+
+	return nil
+}
+
+// Deletes the member at path, together with its comma and, if it is on lines of its own, those lines.
+// Returns KeyNotFoundError or ErrIndexOutOfRange if there is no such member.
+func (d *Document) Delete(path Path) error {
+	if len(path) == 0 {
+		return errors.New("jason: can't delete the document root")
+	}
+
+	node := d.root
+
+	for i, element := range path {
+		j, err := node.member(element)
+
+		if err != nil {
+			return err
+		}
+
+		if j < 0 {
+			if node.kind == '[' {
+				return ErrIndexOutOfRange
+			}
+
+			key, _, _ := pathElement(element)
+
+			return KeyNotFoundError{key}
+		}
+
+		if i == len(path)-1 {
+			return d.remove(node, j)
+		}
+
+		node = node.members[j].value
+	}
+
+	return nil
+}
+
+// Finds the member of an object or array named by a path element, or -1 if there is none.
+// Returns ErrNotObject or ErrNotArray for elements that don't fit the node.
+func (n *cstNode) member(element interface{}) (int, error) {
+	key, index, err := pathElement(element)
+
+	if err != nil {
+		return -1, err
+	}
+
+	switch n.kind {
+	case '{':
+		// Like the parser, the last of duplicate keys wins.
+		for j := len(n.members) - 1; j >= 0; j-- {
+			if n.members[j].key == key {
+				return j, nil
+			}
+		}
+
+		return -1, nil
+	case '[':
+		if _, ok := element.(int); !ok && index < 0 {
+			return -1, ErrNotObject
+		}
+
+		if index < 0 || index >= len(n.members) {
+			return -1, nil
+		}
+
+		return index, nil
+	}
+
+	if _, ok := element.(int); ok {
+		return -1, ErrNotArray
+	}
+
+	return -1, ErrNotObject
+}
+
+// Reports whether an object or array spans several lines.
+func (n *cstNode) isMultiline(src []byte) bool {
+	return bytes.IndexByte(src[n.start:n.end], '\n') >= 0
+}
+
+// Adds a member at the end of an object, or an element if key is nil.
+func (d *Document) insert(n *cstNode, key *string, data interface{}) error {
+	separator := d.separator(n)
+	member := ""
+
+	if key != nil {
+		text, err := d.encode(*key, "", false, separator)
+
+		if err != nil {
+			return err
+		}
+
+		member = text + separator
+	}
+
+	if len(n.members) == 0 {
+		indent := d.lineIndent(n.start)
+		interior := d.src[n.start+1 : n.end-1]
+
+		if bytes.IndexByte(d.src, '\n') < 0 || (n.kind == '[' && !n.isMultiline(d.src)) {
+			text, err := d.encode(data, "", false, separator)
+
+			if err != nil {
+				return err
+			}
+
+			if len(bytes.TrimSpace(interior)) > 0 {
+				return d.splice(n.start+1, n.start+1, member+text+" ")
+			}
+
+			return d.splice(n.start+1, n.end-1, member+text)
+		}
+
+		text, err := d.encode(data, indent+d.indentUnit(), true, separator)
+
+		if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(interior)) == 0 {
+			return d.splice(n.start+1, n.end-1, "\n"+indent+d.indentUnit()+member+text+"\n"+indent)
+		}
+
+		return d.splice(n.start+1, n.start+1, "\n"+indent+d.indentUnit()+member+text)
+	}
+
+	last := n.members[len(n.members)-1]
+	multiline := n.isMultiline(d.src)
+
+	// Start the member like the last one, on a new line or after the comma.
+	lead := " "
+
+	if strings.TrimSpace(separator) == separator {
+		lead = ""
+	}
+
+	if multiline {
+		lead = "\n" + d.lineIndent(last.start)
+	} else if len(n.members) > 1 {
+		previous := n.members[len(n.members)-2]
+		lead = string(d.src[previous.comma+1 : last.start])
+	}
+
+	text, err := d.encode(data, d.lineIndent(last.start), multiline, separator)
+
+	if err != nil {
+		return err
+	}
+
+	if last.comma >= 0 {
+		at := last.comma + 1
+		if multiline {
+			at = d.lineEnd(at)
+		}
+
+		return d.splice(at, at, lead+member+text+",")
+	}
+
+	// Add the comma after the last value, and the member after the comments following it.
+	at := d.commentEnd(last.value.end)
+	if multiline {
+		at = d.lineEnd(at)
+	}
+
+	b := make([]byte, 0, len(d.src)+len(lead)+len(member)+len(text)+1)
+	b = append(b, d.src[:last.value.end]...)
+	b = append(b, ',')
+	b = append(b, d.src[last.value.end:at]...)
+	b = append(b, lead+member+text...)
+	b = append(b, d.src[at:]...)
+
+	return d.parse(b)
+}
+
+// Skips blanks and block comments on the line of pos.
+// Returns the end of the last comment, or pos if there is none.
+func (d *Document) commentEnd(pos int) int {
+	end := pos
+
+	for i := pos; ; {
+		for i < len(d.src) && (d.src[i] == ' ' || d.src[i] == '\t') {
+			i++
+		}
+
+		if !bytes.HasPrefix(d.src[i:], []byte("/*")) {
+			return end
+		}
+
+		length := bytes.Index(d.src[i+2:], []byte("*/"))
+		if length < 0 || bytes.IndexByte(d.src[i:i+2+length], '\n') >= 0 {
+			return end
+		}
+
+		i += length + 4
+		end = i
+	}
+}
+
+// Skips blanks, block comments and a line comment from pos. Returns the position
+// of the line break if only those follow pos on its line, or pos otherwise.
+func (d *Document) lineEnd(pos int) int {
+	end := d.commentEnd(pos)
+	for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+		end++
+	}
+
+	if bytes.HasPrefix(d.src[end:], []byte("//")) {
+		if i := bytes.IndexByte(d.src[end:], '\n'); i >= 0 {
+			return end + i
+		}
+
+		return len(d.src)
+	}
+
+	if end == len(d.src) || d.src[end] == '\n' {
+		return end
+	}
+
+	return pos
+}
+
+// Removes a member of an object or array.
+func (d *Document) remove(n *cstNode, j int) error {
+	m := n.members[j]
+	start, end := m.start, m.value.end
+
+	if m.comma >= 0 {
+		end = m.comma + 1
+	}
+
+	// The member is on lines of its own if only whitespace and a comment surround it.
+	lineStart := bytes.LastIndexByte(d.src[:start], '\n') + 1
+	lineEnd := d.lineEnd(end)
+	ownLines := len(bytes.TrimLeft(d.src[lineStart:start], " \t")) == 0 && (lineEnd == len(d.src) || d.src[lineEnd] == '\n')
+
+	switch {
+	case ownLines:
+		start, end = lineStart, min(lineEnd+1, len(d.src))
+	case m.comma < 0 && j > 0:
+		// Remove the comma before the last member instead of the one after it.
+		return d.splice(n.members[j-1].value.end, end, "")
+	default:
+		for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+			end++
+		}
+	}
+
+	// The member before the last one must not keep its comma, unless trailing commas are used.
+	if m.comma < 0 && j > 0 {
+		comma := n.members[j-1].comma
+
+		b := make([]byte, 0, len(d.src))
+		b = append(b, d.src[:comma]...)
+		b = append(b, d.src[comma+1:start]...)
+		b = append(b, d.src[end:]...)
+
+		return d.parse(b)
+	}
+
+	return d.splice(start, end, "")
+}
+
+// Replaces the bytes between start and end with text and parses the document again.
+func (d *Document) splice(start, end int, text string) error {
+	b := make([]byte, 0, len(d.src)-(end-start)+len(text))
+	b = append(b, d.src[:start]...)
+	b = append(b, text...)
+	b = append(b, d.src[end:]...)
+
+	return d.parse(b)
+}
+
+// Encodes data to be written on a line starting with prefix,
+// indenting objects and arrays if multiline is set. Otherwise they are written on one line,
+// with separator between keys and values and matching spacing after commas.
+func (d *Document) encode(data interface{}, prefix string, multiline bool, separator string) (string, error) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if multiline {
+		enc.SetIndent(prefix, d.indentUnit())
+	}
+
+	if err := enc.Encode(&Value{data, true}); err != nil {
+		return "", err
+	}
+
+	if multiline || separator == ":" {
+		return buf.String(), nil
+	}
+
+	// The compact encoding has no whitespace, so colons and commas outside strings are separators.
+	comma := "," + separator[strings.IndexByte(separator, ':')+1:]
+
+	var b strings.Builder
+	inString := false
+
+	for i, c := range buf.Bytes() {
+		switch {
+		case inString:
+			if c == '"' && !escaped(buf.Bytes()[:i]) {
+				inString = false
+			}
+			b.WriteByte(c)
+		case c == '"':
+			inString = true
+			b.WriteByte(c)
+		case c == ':':
+			b.WriteString(separator)
+		case c == ',':
+			b.WriteString(comma)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// Reports whether the byte after b is escaped by an odd number of backslashes.
+func escaped(b []byte) bool {
+	n := 0
+	for n < len(b) && b[len(b)-1-n] == '\\' {
+		n++
+	}
+
+	return n%2 == 1
+}
+
+// Returns the spacing between keys and values in n, or else in the document, so new members match it.
+// Spacing with comments or line breaks in it is skipped.
+func (d *Document) separator(n *cstNode) string {
+	for _, node := range []*cstNode{n, d.root} {
+		if s, ok := d.findSeparator(node); ok {
+			return s
+		}
+	}
+
+	return ": "
+}
+
+// Finds the spacing of the last member of n or its descendants that has a plain one.
+func (d *Document) findSeparator(n *cstNode) (string, bool) {
+	for j := len(n.members) - 1; j >= 0; j-- {
+		m := n.members[j]
+
+		if n.kind == '{' {
+			if s := d.src[m.keyEnd:m.value.start]; !bytes.ContainsAny(s, "/\n") {
+				return string(s), true
+			}
+		}
+
+		if s, ok := d.findSeparator(m.value); ok {
+			return s, true
+		}
+	}
+
+	return "", false
+}
+
+// Returns the whitespace at the start of the line holding pos.
+func (d *Document) lineIndent(pos int) string {
+	start := bytes.LastIndexByte(d.src[:pos], '\n') + 1
+	end := start
+
+	for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+		end++
+	}
+
+	return string(d.src[start:end])
+}
+
+// Returns the indentation of the first indented line, or two spaces.
+func (d *Document) indentUnit() string {
+	for _, line := range bytes.Split(d.src, []byte("\n")) {
+		if trimmed := bytes.TrimLeft(line, " \t"); len(trimmed) > 0 && len(trimmed) < len(line) {
+			return string(line[:len(line)-len(trimmed)])
+		}
+	}
+
+	return "  "
+}
+
+// Parses a value, recording the location of objects, arrays and their members.
+func (p *relaxedParser) parseNode(depth int) (*cstNode, error) {
+	if depth > maxRelaxedDepth {
+		return nil, p.errorf("exceeded max depth")
+	}
+
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+
+	start := p.pos
+
+	if c := p.peek(); c != '{' && c != '[' {
+		if _, err := p.parseValue(depth); err != nil {
+			return nil, err
+		}
+
+		return &cstNode{start: start, end: p.pos}, nil
+	}
+
+	n := &cstNode{kind: p.peek(), start: start}
+
+	closing := byte('}')
+	if n.kind == '[' {
+		closing = ']'
+	}
+
+	p.pos++
+
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		if p.peek() == closing {
+			p.pos++
+			n.end = p.pos
+
+			return n, nil
+		}
+
+		m := cstMember{start: p.pos, comma: -1}
+
+		if n.kind == '{' {
+			key, err := p.parseKey()
+
+			if err != nil {
+				return nil, err
+			}
+
+			// Record the end of the key, before the colon and any spaces.
+			m.key, m.keyEnd = key, p.pos-1
+			for m.keyEnd > m.start && (p.src[m.keyEnd-1] == ' ' || p.src[m.keyEnd-1] == '\t') {
+				m.keyEnd--
+			}
+		}
+
+		value, err := p.parseNode(depth + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		m.value = value
+
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		switch p.peek() {
+		case ',':
+			m.comma = p.pos
+			p.pos++
+		case closing:
+		default:
+			return nil, p.errorf("expected ',' or '%c'", closing)
+		}
+
+		n.members = append(n.members, m)
+	}
+}
@@ -0,0 +1,235 @@
+package jason
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+const documentSource = `{
+    // Editor settings
+    "editor": {
+        "fontSize": 12, // points
+        "tabSize": 4
+    },
+    "plugins": ["git", "lint"],
+    "theme": "dark"
+}
+`
+
+func editDocument(t *testing.T, source string, edit func(d *Document) error) string {
+	assert := NewAssert(t)
+
+	d, err := ParseDocument([]byte(source), JSON5)
+	assert.True(err == nil, "failed to parse document "+source)
+
+	err = edit(d)
+	assert.True(err == nil, fmt.Sprintf("failed to edit %s: %v", source, err))
+
+	_, err = d.Value()
+	assert.True(err == nil, fmt.Sprintf("edited document is invalid: %v\n%s", err, d.Bytes()))
+
+	return string(d.Bytes())
+}
+
+func documentValue(t *testing.T, data interface{}) *Value {
+	v, err := NewValue(data)
+	NewAssert(t).True(err == nil, fmt.Sprintf("failed to create a value from %v", data))
+
+	return v
+}
+
+func TestDocumentSet(t *testing.T) {
+	assert := NewAssert(t)
+
+	tests := []struct {
+		path     Path
+		value    interface{}
+		expected string
+	}{
+		{Path{"editor", "fontSize"}, 14, `{
+    // Editor settings
+    "editor": {
+        "fontSize": 14, // points
+        "tabSize": 4
+    },
+    "plugins": ["git", "lint"],
+    "theme": "dark"
+}
+`},
+		{Path{"editor", "wordWrap"}, true, `{
+    // Editor settings
+    "editor": {
+        "fontSize": 12, // points
+        "tabSize": 4,
+        "wordWrap": true
+    },
+    "plugins": ["git", "lint"],
+    "theme": "dark"
+}
+`},
+		{Path{"plugins", 2}, "format", `{
+    // Editor settings
+    "editor": {
+        "fontSize": 12, // points
+        "tabSize": 4
+    },
+    "plugins": ["git", "lint", "format"],
+    "theme": "dark"
+}
+`},
+		{Path{"files", "exclude"}, []string{"*.tmp"}, `{
+    // Editor settings
+    "editor": {
+        "fontSize": 12, // points
+        "tabSize": 4
+    },
+    "plugins": ["git", "lint"],
+    "theme": "dark",
+    "files": {
+        "exclude": [
+            "*.tmp"
+        ]
+    }
+}
+`},
+	}
+
+	for _, test := range tests {
+		s := editDocument(t, documentSource, func(d *Document) error {
+			return d.Set(test.path, documentValue(t, test.value))
+		})
+
+		assert.True(s == test.expected, fmt.Sprintf("setting %v: expected\n%s\ngot\n%s", test.path, test.expected, s))
+	}
+}
+
+func TestDocumentDelete(t *testing.T) {
+	assert := NewAssert(t)
+
+	tests := []struct {
+		path     Path
+		expected string
+	}{
+		{Path{"editor", "fontSize"}, `{
+    // Editor settings
+    "editor": {
+        "tabSize": 4
+    },
+    "plugins": ["git", "lint"],
+    "theme": "dark"
+}
+`},
+		{Path{"theme"}, `{
+    // Editor settings
+    "editor": {
+        "fontSize": 12, // points
+        "tabSize": 4
+    },
+    "plugins": ["git", "lint"]
+}
+`},
+		{Path{"plugins", 0}, `{
+    // Editor settings
+    "editor": {
+        "fontSize": 12, // points
+        "tabSize": 4
+    },
+    "plugins": ["lint"],
+    "theme": "dark"
+}
+`},
+		{Path{"plugins", 1}, `{
+    // Editor settings
+    "editor": {
+        "fontSize": 12, // points
+        "tabSize": 4
+    },
+    "plugins": ["git"],
+    "theme": "dark"
+}
+`},
+	}
+
+	for _, test := range tests {
+		s := editDocument(t, documentSource, func(d *Document) error {
+			return d.Delete(test.path)
+		})
+
+		assert.True(s == test.expected, fmt.Sprintf("deleting %v: expected\n%s\ngot\n%s", test.path, test.expected, s))
+	}
+}
+
+func TestDocumentEdits(t *testing.T) {
+	assert := NewAssert(t)
+
+	tests := []struct {
+		source   string
+		edit     func(d *Document) error
+		expected string
+	}{
+		{`{"a":1}`, func(d *Document) error { return d.Set(Path{"b"}, documentValue(t, 2)) }, `{"a":1,"b":2}`},
+		{`{}`, func(d *Document) error { return d.Set(Path{"a"}, documentValue(t, []int{1})) }, `{"a": [1]}`},
+		{"{\n  \"a\": {}\n}", func(d *Document) error { return d.Set(Path{"a", "b"}, documentValue(t, 1)) },
+			"{\n  \"a\": {\n    \"b\": 1\n  }\n}"},
+		{"{\n  \"a\": 1,\n  \"b\": 2,\n}", func(d *Document) error { return d.Set(Path{"c"}, documentValue(t, 3)) },
+			"{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3,\n}"},
+		{"{\n  \"a\": 1, // one\n  \"b\": 2 // two\n}", func(d *Document) error { return d.Delete(Path{"b"}) },
+			"{\n  \"a\": 1 // one\n}"},
+		{"{\n  \"a\": 1 // one\n}", func(d *Document) error { return d.Set(Path{"b"}, documentValue(t, 2)) },
+			"{\n  \"a\": 1, // one\n  \"b\": 2\n}"},
+		{`{"a": 1, "b": 2, "c": 3}`, func(d *Document) error { return d.Delete(Path{"a"}) }, `{"b": 2, "c": 3}`},
+		{`{"a": 1, "b": 2, "c": 3}`, func(d *Document) error { return d.Delete(Path{"c"}) }, `{"a": 1, "b": 2}`},
+		{`{"a": 1}`, func(d *Document) error { return d.Set(Path{}, documentValue(t, "root")) }, `"root"`},
+		{`{a: 'x', /* keep */ b: 0x10}`, func(d *Document) error { return d.Set(Path{"a"}, documentValue(t, "y")) },
+			`{a: "y", /* keep */ b: 0x10}`},
+		{`{"a": 1}`, func(d *Document) error { return d.Set(Path{"b", "c"}, documentValue(t, "x:y, \\\"z\\\"")) },
+			`{"a": 1, "b": {"c": "x:y, \\\"z\\\""}}`},
+		{`{"a" : {}}`, func(d *Document) error { return d.Set(Path{"a", "b", "c"}, documentValue(t, []int{1, 2})) },
+			`{"a" : {"b" : {"c" : [1, 2]}}}`},
+		{`{"a":[]}`, func(d *Document) error { return d.Set(Path{"a", 0}, documentValue(t, map[string]int{"b": 1, "c": 2})) },
+			`{"a":[{"b":1,"c":2}]}`},
+		{"{\n  \"a\": 1 /* one */\n}", func(d *Document) error { return d.Set(Path{"b"}, documentValue(t, 2)) },
+			"{\n  \"a\": 1, /* one */\n  \"b\": 2\n}"},
+		{"{\n  \"a\": 1, /* one */\n  \"b\": 2 /* two */ // 2\n}", func(d *Document) error { return d.Set(Path{"c"}, documentValue(t, 3)) },
+			"{\n  \"a\": 1, /* one */\n  \"b\": 2, /* two */ // 2\n  \"c\": 3\n}"},
+		{`{"a": 1 /* one */}`, func(d *Document) error { return d.Set(Path{"b"}, documentValue(t, 2)) }, `{"a": 1, /* one */ "b": 2}`},
+		{"{\n  \"a\": 1, /* one */\n  \"b\": 2 /* two */\n}", func(d *Document) error { return d.Delete(Path{"b"}) },
+			"{\n  \"a\": 1 /* one */\n}"},
+		{"{\n  \"a\": 1, /* one */\n  \"b\": 2\n}", func(d *Document) error { return d.Delete(Path{"a"}) },
+			"{\n  \"b\": 2\n}"},
+	}
+
+	for _, test := range tests {
+		s := editDocument(t, test.source, test.edit)
+
+		assert.True(s == test.expected, fmt.Sprintf("editing %q: expected %q, got %q", test.source, test.expected, s))
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	assert := NewAssert(t)
+
+	d, err := ParseDocument([]byte(`{"a": [1], "b": "x"}`))
+	assert.True(err == nil, "failed to parse document")
+
+	one := documentValue(t, 1)
+
+	err = d.Delete(Path{"missing"})
+	assert.True(errors.As(err, new(KeyNotFoundError)), "deleting a missing key should return KeyNotFoundError")
+
+	err = d.Set(Path{"a", 5}, one)
+	assert.True(err == ErrIndexOutOfRange, "setting past the end of an array should return ErrIndexOutOfRange")
+
+	err = d.Set(Path{"b", "c"}, one)
+	assert.True(err == ErrNotObject, "setting a key in a string should return ErrNotObject")
+
+	err = d.Delete(Path{})
+	assert.True(err != nil, "deleting the root should fail")
+
+	_, err = ParseDocument([]byte(`{"a": 1 // comment
+	}`))
+	assert.True(err != nil, "comments should be rejected without JSONC")
+
+	assert.True(string(d.Bytes()) == `{"a": [1], "b": "x"}`, "failed edits should not change the document")
+}
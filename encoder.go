@@ -0,0 +1,368 @@
+package jason
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// An Encoder writes JSON values to an output stream.
+// By default it produces the same compact output as Value.Marshal:
+// object keys are sorted and HTML characters are escaped.
+// Use the Set methods to configure the output before calling Encode.
+// Example:
+//		enc := jason.NewEncoder(w)
+//		enc.SetIndent("", "  ")
+//		err := enc.Encode(v)
+type Encoder struct {
+	w          io.Writer
+	prefix     string
+	indent     string
+	sortKeys   bool
+	escapeHTML bool
+	asciiOnly  bool
+	newline    bool
+	maxWidth   int
+}
+
+// Creates a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:          w,
+		sortKeys:   true,
+		escapeHTML: true,
+	}
+}
+
+// Makes the encoder format each element on its own line, beginning with prefix
+// followed by one or more copies of indent according to the nesting depth.
+// Calling SetIndent("", "") disables indentation.
+func (e *Encoder) SetIndent(prefix, indent string) {
+	e.prefix = prefix
+	e.indent = indent
+}
+
+// Specifies whether object keys should be written in sorted order. Default is true.
+// Disabling it is slightly faster, but the key order is then unspecified.
+func (e *Encoder) SetSortKeys(on bool) {
+	e.sortKeys = on
+}
+
+// Specifies whether the characters <, > and & should be escaped inside strings,
+// so the output can be safely embedded in HTML. Default is true.
+func (e *Encoder) SetEscapeHTML(on bool) {
+	e.escapeHTML = on
+}
+
+// Specifies whether all non-ASCII characters should be written as \u escapes. Default is false.
+func (e *Encoder) SetASCII(on bool) {
+	e.asciiOnly = on
+}
+
+// Specifies whether a newline should be written after each encoded value. Default is false.
+func (e *Encoder) SetTrailingNewline(on bool) {
+	e.newline = on
+}
+
+// Sets the maximum line width used when indenting.
+// Arrays that only contain strings, numbers, booleans and nulls are written
+// on a single line if that line, including its indentation, fits within width.
+// A width of zero, the default, writes every element on its own line.
+func (e *Encoder) SetMaxWidth(width int) {
+	e.maxWidth = width
+}
+
+// Writes the JSON encoding of v to the stream.
+// Returns an error if the value contains invalid data or if writing fails.
+func (e *Encoder) Encode(v *Value) error {
+	w := bufio.NewWriter(e.w)
+
+	err := e.encode(w, v.data, 0, len(e.prefix))
+
+	if err != nil {
+		return err
+	}
+
+	if e.newline {
+		w.WriteByte('\n')
+	}
+
+	return w.Flush()
+}
+
+func (e *Encoder) encode(w *bufio.Writer, data interface{}, depth int, column int) error {
+	switch data := data.(type) {
+	case nil:
+		w.WriteString("null")
+	case bool:
+		w.WriteString(strconv.FormatBool(data))
+	case json.Number:
+		if !isValidNumber(string(data)) {
+			return fmt.Errorf("jason: invalid number literal %q", string(data))
+		}
+		w.WriteString(string(data))
+	case string:
+		e.writeString(w, data)
+	case []interface{}:
+		return e.encodeArray(w, data, depth, column)
+	case map[string]interface{}:
+		return e.encodeObject(w, data, depth)
+	default:
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		w.Write(b)
+	}
+
+	return nil
+}
+
+func (e *Encoder) encodeArray(w *bufio.Writer, array []interface{}, depth int, column int) error {
+	if len(array) == 0 {
+		w.WriteString("[]")
+		return nil
+	}
+
+	if e.indent != "" && e.maxWidth > 0 {
+		if line, ok := e.compactLine(array); ok && column+len(line) <= e.maxWidth {
+			w.WriteString(line)
+			return nil
+		}
+	}
+
+	w.WriteByte('[')
+
+	for index, element := range array {
+		if index > 0 {
+			w.WriteByte(',')
+		}
+
+		e.writeNewline(w, depth+1)
+
+		err := e.encode(w, element, depth+1, e.lineStart(depth+1))
+		if err != nil {
+			return err
+		}
+	}
+
+	e.writeNewline(w, depth)
+	w.WriteByte(']')
+
+	return nil
+}
+
+func (e *Encoder) encodeObject(w *bufio.Writer, object map[string]interface{}, depth int) error {
+	if len(object) == 0 {
+		w.WriteString("{}")
+		return nil
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	if e.sortKeys {
+		sort.Strings(keys)
+	}
+
+	w.WriteByte('{')
+
+	for index, key := range keys {
+		if index > 0 {
+			w.WriteByte(',')
+		}
+
+		e.writeNewline(w, depth+1)
+
+		e.writeString(w, key)
+		w.WriteByte(':')
+		if e.indent != "" {
+			w.WriteByte(' ')
+		}
+
+		err := e.encode(w, object[key], depth+1, e.lineStart(depth+1)+e.keyWidth(key))
+		if err != nil {
+			return err
+		}
+	}
+
+	e.writeNewline(w, depth)
+	w.WriteByte('}')
+
+	return nil
+}
+
+// Returns the array written on a single line, if all elements are scalars.
+func (e *Encoder) compactLine(array []interface{}) (string, bool) {
+	var line bytes.Buffer
+	lw := bufio.NewWriter(&line)
+
+	lw.WriteByte('[')
+	for index, element := range array {
+		switch element.(type) {
+		case []interface{}, map[string]interface{}:
+			return "", false
+		}
+
+		if index > 0 {
+			lw.WriteString(", ")
+		}
+
+		if err := e.encode(lw, element, 0, 0); err != nil {
+			return "", false
+		}
+	}
+	lw.WriteByte(']')
+	lw.Flush()
+
+	return line.String(), true
+}
+
+// Returns the column where a line at the given depth starts.
+func (e *Encoder) lineStart(depth int) int {
+	return len(e.prefix) + depth*len(e.indent)
+}
+
+// Returns the width of an encoded object key, including the separator.
+func (e *Encoder) keyWidth(key string) int {
+	var line bytes.Buffer
+	lw := bufio.NewWriter(&line)
+	e.writeString(lw, key)
+	lw.Flush()
+
+	if e.indent != "" {
+		return line.Len() + 2
+	}
+
+	return line.Len() + 1
+}
+
+func (e *Encoder) writeNewline(w *bufio.Writer, depth int) {
+	if e.indent == "" {
+		return
+	}
+
+	w.WriteByte('\n')
+	w.WriteString(e.prefix)
+	for i := 0; i < depth; i++ {
+		w.WriteString(e.indent)
+	}
+}
+
+const hexDigits = "0123456789abcdef"
+
+func (e *Encoder) writeString(w *bufio.Writer, s string) {
+	w.WriteByte('"')
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				w.WriteByte('\\')
+				w.WriteByte(c)
+			case c == '\n':
+				w.WriteString(`\n`)
+			case c == '\r':
+				w.WriteString(`\r`)
+			case c == '\t':
+				w.WriteString(`\t`)
+			case c < 0x20 || (e.escapeHTML && (c == '<' || c == '>' || c == '&')):
+				w.WriteString(`\u00`)
+				w.WriteByte(hexDigits[c>>4])
+				w.WriteByte(hexDigits[c&0xF])
+			default:
+				w.WriteByte(c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+			w.WriteString(`\ufffd`)
+		case r == '\u2028' || r == '\u2029' || e.asciiOnly:
+			writeRuneEscape(w, r)
+		default:
+			w.WriteString(s[i : i+size])
+		}
+
+		i += size
+	}
+
+	w.WriteByte('"')
+}
+
+// Writes r as one \u escape, or as a surrogate pair outside the basic multilingual plane.
+func writeRuneEscape(w *bufio.Writer, r rune) {
+	if r > 0xFFFF {
+		r -= 0x10000
+		writeRuneEscape(w, 0xD800+(r>>10)&0x3FF)
+		writeRuneEscape(w, 0xDC00+r&0x3FF)
+		return
+	}
+
+	w.WriteString(`\u`)
+	w.WriteByte(hexDigits[r>>12&0xF])
+	w.WriteByte(hexDigits[r>>8&0xF])
+	w.WriteByte(hexDigits[r>>4&0xF])
+	w.WriteByte(hexDigits[r&0xF])
+}
+
+// Reports whether s is a valid JSON number literal.
+func isValidNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	if s[0] == '-' {
+		s = s[1:]
+		if s == "" {
+			return false
+		}
+	}
+
+	switch {
+	case s[0] == '0':
+		s = s[1:]
+	case '1' <= s[0] && s[0] <= '9':
+		s = s[1:]
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	default:
+		return false
+	}
+
+	if len(s) >= 2 && s[0] == '.' && '0' <= s[1] && s[1] <= '9' {
+		s = s[2:]
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	}
+
+	if len(s) >= 2 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s[0] == '+' || s[0] == '-' {
+			s = s[1:]
+			if s == "" {
+				return false
+			}
+		}
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	}
+
+	return s == ""
+}
@@ -0,0 +1,101 @@
+package jason
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func encodeString(t *testing.T, s string, configure func(e *Encoder)) string {
+	assert := NewAssert(t)
+
+	v, err := NewValueFromBytes([]byte(s))
+	assert.True(err == nil, "failed to parse "+s)
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	if configure != nil {
+		configure(e)
+	}
+
+	assert.True(e.Encode(v) == nil, "failed to encode "+s)
+
+	return buf.String()
+}
+
+func TestEncoderDefaults(t *testing.T) {
+	assert := NewAssert(t)
+
+	input := `{"b": [1, 2.50, "<a&b>"], "a": {"z": null, "y": true}, "c": " "}`
+
+	v, _ := NewValueFromBytes([]byte(input))
+	marshaled, _ := v.Marshal()
+
+	s := encodeString(t, input, nil)
+	assert.True(s == string(marshaled), "default encoder output should match Marshal, got "+s)
+}
+
+func TestEncoderIndent(t *testing.T) {
+	assert := NewAssert(t)
+
+	s := encodeString(t, `{"name": "anton", "list": [1, {"a": []}], "empty": {}}`, func(e *Encoder) {
+		e.SetIndent("", "  ")
+		e.SetTrailingNewline(true)
+	})
+
+	expected := `{
+  "empty": {},
+  "list": [
+    1,
+    {
+      "a": []
+    }
+  ],
+  "name": "anton"
+}
+`
+	assert.True(s == expected, "unexpected indented output:\n"+s)
+}
+
+func TestEncoderMaxWidth(t *testing.T) {
+	assert := NewAssert(t)
+
+	s := encodeString(t, `{"short": [1, 2, 3], "long": ["aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc"], "nested": [[1]]}`, func(e *Encoder) {
+		e.SetIndent("", "  ")
+		e.SetMaxWidth(30)
+	})
+
+	expected := `{
+  "long": [
+    "aaaaaaaaaa",
+    "bbbbbbbbbb",
+    "cccccccccc"
+  ],
+  "nested": [
+    [1]
+  ],
+  "short": [1, 2, 3]
+}`
+	assert.True(s == expected, "unexpected compacted output:\n"+s)
+}
+
+func TestEncoderEscaping(t *testing.T) {
+	assert := NewAssert(t)
+
+	input := `"<tag> & \"quoted\" hé 😀 \u0001"`
+
+	s := encodeString(t, input, func(e *Encoder) { e.SetEscapeHTML(false) })
+	assert.True(s == `"<tag> & \"quoted\" hé 😀 \u0001"`, "unexpected output without html escaping: "+s)
+
+	s = encodeString(t, input, func(e *Encoder) { e.SetASCII(true) })
+	assert.True(s == `"\u003ctag\u003e \u0026 \"quoted\" h\u00e9 \ud83d\ude00 \u0001"`, "unexpected ascii output: "+s)
+}
+
+func TestEncoderInvalidNumber(t *testing.T) {
+	assert := NewAssert(t)
+
+	var buf bytes.Buffer
+
+	v := &Value{data: []interface{}{json.Number("01")}, exists: true}
+	assert.True(NewEncoder(&buf).Encode(v) != nil, "invalid number literals should return an error")
+}
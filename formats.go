@@ -0,0 +1,235 @@
+package jason
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+)
+
+// FormatError is returned when a string is not valid in the format it is read as.
+type FormatError struct {
+	Path   Path   // Location of the string, empty if unknown
+	Format string // The expected format, like "url"
+	Value  string // The string as written
+	Err    error  // The parse error
+}
+
+func (e *FormatError) Error() string {
+	location := ""
+	if len(e.Path) > 0 {
+		location = " at " + e.Path.String()
+	}
+
+	return fmt.Sprintf("%q%s is not a valid %s: %v", e.Value, location, e.Format, e.Err)
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// UUID is a universally unique identifier (RFC 9562).
+type UUID [16]byte
+
+// Returns the UUID in its canonical form, like 123e4567-e89b-12d3-a456-426614174000.
+func (u UUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// Parses a UUID in its canonical form. Upper and lower case digits are accepted.
+func parseUUID(s string) (UUID, error) {
+	var u UUID
+
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, errors.New("expected the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+	}
+
+	digits := s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+
+	if n, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return u, err
+	} else if n != len(u) {
+		return u, errors.New("expected 32 hex digits")
+	}
+
+	return u, nil
+}
+
+// Reads the current value as a string and parses it, wrapping parse errors in a *FormatError.
+func parseString[T any](v *Value, format string, parse func(string) (T, error)) (T, error) {
+	var zero T
+
+	s, err := v.String()
+
+	if err != nil {
+		return zero, err
+	}
+
+	parsed, err := parse(s)
+
+	if err != nil {
+		return zero, &FormatError{Format: format, Value: s, Err: err}
+	}
+
+	return parsed, nil
+}
+
+// Attempts to typecast the current value into an absolute URL, like https://example.com/callback.
+// Returns error if the current value is not a json string, or a *FormatError if it is not an absolute URL.
+// Example:
+//		callback, err := v.URL()
+func (v *Value) URL() (*url.URL, error) {
+	return parseString(v, "url", func(s string) (*url.URL, error) {
+		u, err := url.Parse(s)
+
+		if err == nil && !u.IsAbs() {
+			err = errors.New("missing scheme")
+		}
+
+		return u, err
+	})
+}
+
+// Attempts to typecast the current value into a UUID.
+// Returns error if the current value is not a json string, or a *FormatError if it is not a UUID.
+// Example:
+//		id, err := v.UUID()
+func (v *Value) UUID() (UUID, error) {
+	return parseString(v, "uuid", parseUUID)
+}
+
+// Attempts to typecast the current value into an IPv4 or IPv6 address.
+// Returns error if the current value is not a json string, or a *FormatError if it is not an IP address.
+// Example:
+//		addr, err := v.IP()
+func (v *Value) IP() (netip.Addr, error) {
+	return parseString(v, "ip address", netip.ParseAddr)
+}
+
+// Attempts to typecast the current value into an IP prefix in CIDR notation, like 10.0.0.0/8.
+// Returns error if the current value is not a json string, or a *FormatError if it is not a prefix.
+// Example:
+//		network, err := v.IPPrefix()
+func (v *Value) IPPrefix() (netip.Prefix, error) {
+	return parseString(v, "ip prefix", netip.ParsePrefix)
+}
+
+// Attempts to typecast the current value into an email address (RFC 5322),
+// either bare like anton@example.com or with a name like "Anton <anton@example.com>".
+// Returns error if the current value is not a json string, or a *FormatError if it is not an address.
+// Example:
+//		address, err := v.Email()
+func (v *Value) Email() (*mail.Address, error) {
+	return parseString(v, "email address", mail.ParseAddress)
+}
+
+// Gets the value at key path and parses it with the given accessor, recording the path in format errors.
+func getFormat[T any](v *Object, keys []string, parse func(*Value) (T, error)) (T, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	parsed, err := parse(child)
+
+	return parsed, withPath(err, keyPath(keys))
+}
+
+// Gets the array at key path and parses every item with the given accessor, recording the path in format errors.
+func getFormatArray[T any](v *Object, keys []string, parse func(*Value) (T, error)) ([]T, error) {
+	array, err := v.GetValueArray(keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typedArray := make([]T, len(array))
+
+	for index, arrayItem := range array {
+		if typedArray[index], err = parse(arrayItem); err != nil {
+			return nil, withPath(err, keyPath(keys).Append(index))
+		}
+	}
+
+	return typedArray, nil
+}
+
+// Gets the value at key path and attempts to typecast the value into an absolute URL.
+// Returns error if the value is not a json string, or a *FormatError naming the key path if it is not an absolute URL.
+// Example:
+//		callback, err := GetURL("webhook", "url")
+func (v *Object) GetURL(keys ...string) (*url.URL, error) {
+	return getFormat(v, keys, (*Value).URL)
+}
+
+// Gets the value at key path and attempts to typecast the value into a UUID.
+// Returns error if the value is not a json string, or a *FormatError naming the key path if it is not a UUID.
+// Example:
+//		id, err := GetUUID("user", "id")
+func (v *Object) GetUUID(keys ...string) (UUID, error) {
+	return getFormat(v, keys, (*Value).UUID)
+}
+
+// Gets the value at key path and attempts to typecast the value into an IP address.
+// Returns error if the value is not a json string, or a *FormatError naming the key path if it is not an IP address.
+// Example:
+//		addr, err := GetIP("server", "address")
+func (v *Object) GetIP(keys ...string) (netip.Addr, error) {
+	return getFormat(v, keys, (*Value).IP)
+}
+
+// Gets the value at key path and attempts to typecast the value into an IP prefix in CIDR notation.
+// Returns error if the value is not a json string, or a *FormatError naming the key path if it is not a prefix.
+// Example:
+//		network, err := GetIPPrefix("server", "network")
+func (v *Object) GetIPPrefix(keys ...string) (netip.Prefix, error) {
+	return getFormat(v, keys, (*Value).IPPrefix)
+}
+
+// Gets the value at key path and attempts to typecast the value into an email address.
+// Returns error if the value is not a json string, or a *FormatError naming the key path if it is not an address.
+// Example:
+//		address, err := GetEmail("user", "email")
+func (v *Object) GetEmail(keys ...string) (*mail.Address, error) {
+	return getFormat(v, keys, (*Value).Email)
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of absolute URLs.
+// Returns error if the value is not a json array or if any of the contained values are not URL strings.
+// A *FormatError names the path of the invalid item.
+// Example:
+//		callbacks, err := GetURLArray("webhook", "callbacks")
+func (v *Object) GetURLArray(keys ...string) ([]*url.URL, error) {
+	return getFormatArray(v, keys, (*Value).URL)
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of UUIDs.
+// Returns error like GetURLArray.
+func (v *Object) GetUUIDArray(keys ...string) ([]UUID, error) {
+	return getFormatArray(v, keys, (*Value).UUID)
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of IP addresses.
+// Returns error like GetURLArray.
+func (v *Object) GetIPArray(keys ...string) ([]netip.Addr, error) {
+	return getFormatArray(v, keys, (*Value).IP)
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of IP prefixes.
+// Returns error like GetURLArray.
+// Example:
+//		allowlist, err := GetIPPrefixArray("firewall", "allow")
+func (v *Object) GetIPPrefixArray(keys ...string) ([]netip.Prefix, error) {
+	return getFormatArray(v, keys, (*Value).IPPrefix)
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of email addresses.
+// Returns error like GetURLArray.
+func (v *Object) GetEmailArray(keys ...string) ([]*mail.Address, error) {
+	return getFormatArray(v, keys, (*Value).Email)
+}
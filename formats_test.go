@@ -0,0 +1,67 @@
+package jason
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormats(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{
+		"webhook": {
+			"url": "https://example.com/callback?x=1",
+			"relative": "/callback",
+			"callbacks": ["https://a.example.com", "http://b.example.com/hook"]
+		},
+		"id": "123E4567-e89b-12d3-a456-426614174000",
+		"badID": "123e4567e89b12d3a456426614174000",
+		"shortID": "12345678-1234-1234-1234-1234567890--",
+		"ip": "2001:db8::1",
+		"network": "10.0.0.0/8",
+		"allow": ["192.168.0.0/16", "fd00::/8", "10.0.0.1"],
+		"email": "Anton <anton@example.com>",
+		"emails": ["a@example.com", "b@example.com"],
+		"number": 1
+	}`))
+	assert.True(err == nil, "failed to parse object")
+
+	u, err := o.GetURL("webhook", "url")
+	assert.True(err == nil && u.Host == "example.com" && u.Query().Get("x") == "1", "absolute urls should parse")
+
+	_, err = o.GetURL("webhook", "relative")
+	var formatErr *FormatError
+	assert.True(errors.As(err, &formatErr), "relative urls should return a *FormatError")
+	assert.True(formatErr.Path.String() == "$.webhook.relative" && formatErr.Format == "url" && formatErr.Value == "/callback", "FormatError should name the path, format and value")
+
+	callbacks, err := o.GetURLArray("webhook", "callbacks")
+	assert.True(err == nil && len(callbacks) == 2 && callbacks[1].Path == "/hook", "url arrays should parse")
+
+	id, err := o.GetUUID("id")
+	assert.True(err == nil && id.String() == "123e4567-e89b-12d3-a456-426614174000", "uuids should parse and format canonically")
+
+	_, err = o.GetUUID("badID")
+	assert.True(errors.As(err, &formatErr), "uuids without dashes should return a *FormatError")
+
+	_, err = o.GetUUID("shortID")
+	assert.True(errors.As(err, &formatErr), "uuids with dashes in place of digits should return a *FormatError")
+
+	ip, err := o.GetIP("ip")
+	assert.True(err == nil && ip.Is6(), "ipv6 addresses should parse")
+
+	network, err := o.GetIPPrefix("network")
+	assert.True(err == nil && network.Bits() == 8 && !network.Contains(ip), "prefixes should parse")
+
+	_, err = o.GetIPPrefixArray("allow")
+	assert.True(errors.As(err, &formatErr) && formatErr.Path.String() == "$.allow[2]", "array format errors should name the item")
+	assert.True(err.Error() == `"10.0.0.1" at $.allow[2] is not a valid ip prefix: `+formatErr.Err.Error(), "unexpected message: "+err.Error())
+
+	email, err := o.GetEmail("email")
+	assert.True(err == nil && email.Name == "Anton" && email.Address == "anton@example.com", "addresses with names should parse")
+
+	emails, err := o.GetEmailArray("emails")
+	assert.True(err == nil && len(emails) == 2 && emails[1].Address == "b@example.com", "email arrays should parse")
+
+	_, err = o.GetIP("number")
+	assert.True(err == ErrNotString, "numbers should return ErrNotString")
+}
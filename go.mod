@@ -0,0 +1,3 @@
+module github.com/antonholmquist/jason
+
+go 1.23
@@ -0,0 +1,244 @@
+package jason
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Type tags written in front of every hashed value, so that for instance
+// the string "1" and the number 1 never produce the same byte stream.
+const (
+	hashNull   = 'n'
+	hashTrue   = 't'
+	hashFalse  = 'f'
+	hashNumber = 'd'
+	hashString = 's'
+	hashArray  = 'a'
+	hashObject = 'o'
+)
+
+// Writes a structural digest of the value into h.
+// The value is walked directly, without first marshaling it into bytes.
+// Object keys are hashed in sorted order and numbers are hashed by their
+// numeric value, so values that are Equal always produce the same hash.
+// Example:
+//		h := sha256.New()
+//		spec.Hash(h)
+//		sum := h.Sum(nil)
+func (v *Value) Hash(h hash.Hash) {
+	writeHash(h, v.data)
+}
+
+// Returns the SHA-256 digest of the value, as computed by Hash.
+// Useful for deduplicating records or detecting changed subtrees.
+// Example:
+//		before := spec.Sum256()
+func (v *Value) Sum256() [sha256.Size]byte {
+	var sum [sha256.Size]byte
+
+	h := sha256.New()
+	v.Hash(h)
+	h.Sum(sum[:0])
+
+	return sum
+}
+
+// Reports whether two values are structurally equal.
+// Numbers are compared by value rather than by their textual representation,
+// so 1, 1.0 and 1e0 are all considered equal. Object key order is not significant.
+// Example:
+//		changed := !before.Equal(after)
+func (v *Value) Equal(other *Value) bool {
+	if v == nil || other == nil {
+		return v == other
+	}
+
+	return equalData(v.data, other.data)
+}
+
+func equalData(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case json.Number:
+		b, ok := b.(json.Number)
+		return ok && numberKey(a) == numberKey(b)
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalData(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, element := range a {
+			other, ok := b[key]
+			if !ok || !equalData(element, other) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+func writeHash(h hash.Hash, data interface{}) {
+	var scratch [8]byte
+
+	writeLength := func(n int) {
+		binary.BigEndian.PutUint64(scratch[:], uint64(n))
+		h.Write(scratch[:])
+	}
+
+	writeString := func(s string) {
+		writeLength(len(s))
+		h.Write([]byte(s))
+	}
+
+	switch data := data.(type) {
+	case nil:
+		h.Write([]byte{hashNull})
+	case bool:
+		if data {
+			h.Write([]byte{hashTrue})
+		} else {
+			h.Write([]byte{hashFalse})
+		}
+	case json.Number:
+		h.Write([]byte{hashNumber})
+		writeString(numberKey(data))
+	case string:
+		h.Write([]byte{hashString})
+		writeString(data)
+	case []interface{}:
+		h.Write([]byte{hashArray})
+		writeLength(len(data))
+		for _, element := range data {
+			writeHash(h, element)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		h.Write([]byte{hashObject})
+		writeLength(len(keys))
+		for _, key := range keys {
+			writeString(key)
+			writeHash(h, data[key])
+		}
+	}
+}
+
+// decimal is a normalized representation of a JSON number.
+// The number equals 0.digits × 10^exp, with digits stripped of leading and
+// trailing zeros. Zero is represented by empty digits regardless of sign.
+type decimal struct {
+	neg    bool
+	digits string
+	exp    int
+	valid  bool
+}
+
+// Returns the normalized form as a string.
+func (d decimal) String() string {
+	if d.digits == "" {
+		return "0"
+	}
+
+	sign := ""
+	if d.neg {
+		sign = "-"
+	}
+
+	return sign + "0." + d.digits + "e" + strconv.Itoa(d.exp)
+}
+
+// Returns a string that is identical for numerically equal numbers.
+// Malformed numbers fall back to their literal text.
+func numberKey(n json.Number) string {
+	d := parseDecimal(n)
+	if !d.valid {
+		return "?" + string(n)
+	}
+
+	return d.String()
+}
+
+// Parses the textual form of a JSON number into a normalized decimal.
+// Arbitrarily long mantissas are handled exactly, without going through float64.
+func parseDecimal(n json.Number) decimal {
+	s := string(n)
+	d := decimal{}
+
+	if strings.HasPrefix(s, "-") {
+		d.neg = true
+		s = s[1:]
+	} else if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return decimal{}
+		}
+		exp = e
+		s = s[:i]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	if intPart == "" && fracPart == "" {
+		return decimal{}
+	}
+
+	for _, c := range intPart + fracPart {
+		if c < '0' || c > '9' {
+			return decimal{}
+		}
+	}
+
+	digits := intPart + fracPart
+	exp += len(intPart)
+
+	trimmed := strings.TrimLeft(digits, "0")
+	exp -= len(digits) - len(trimmed)
+	digits = strings.TrimRight(trimmed, "0")
+
+	d.valid = true
+	if digits == "" {
+		return decimal{valid: true}
+	}
+
+	d.digits = digits
+	d.exp = exp
+
+	return d
+}
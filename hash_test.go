@@ -0,0 +1,66 @@
+package jason
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	assert := NewAssert(t)
+
+	a, err := NewObjectFromBytes([]byte(`{"name": "anton", "age": 29, "list": [1, 2.50, true, null], "spec": {"replicas": 3}}`))
+	assert.True(err == nil, "failed to parse a")
+
+	b, err := NewObjectFromBytes([]byte(`{"spec": {"replicas": 3.0}, "list": [1.0, 25e-1, true, null], "age": 2.9e1, "name": "anton"}`))
+	assert.True(err == nil, "failed to parse b")
+
+	assert.True(a.Equal(&b.Value), "numerically equal documents should be equal")
+	assert.True(a.Sum256() == b.Sum256(), "equal documents should have equal hashes")
+
+	specA, _ := a.GetObject("spec")
+	specB, _ := b.GetObject("spec")
+	assert.True(specA.Sum256() == specB.Sum256(), "equal subtrees should have equal hashes")
+
+	h := sha256.New()
+	specA.Hash(h)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	assert.True(sum == specA.Sum256(), "Sum256 should match Hash with sha256")
+
+	different := []string{
+		`{"name": "anton", "age": 30, "list": [1, 2.50, true, null], "spec": {"replicas": 3}}`,
+		`{"name": "anton", "age": "29", "list": [1, 2.50, true, null], "spec": {"replicas": 3}}`,
+		`{"name": "anton", "age": 29, "list": [2.50, 1, true, null], "spec": {"replicas": 3}}`,
+		`{"name": "anton", "age": 29, "list": [1, 2.50, true], "spec": {"replicas": 3}}`,
+		`{"name": "anton", "age": 29, "list": [1, 2.50, true, null], "spec": {"replicas": 3, "x": null}}`,
+	}
+
+	for _, s := range different {
+		c, err := NewObjectFromBytes([]byte(s))
+		assert.True(err == nil, "failed to parse "+s)
+		assert.True(!a.Equal(&c.Value), "documents should differ: "+s)
+		assert.True(a.Sum256() != c.Sum256(), "hashes should differ: "+s)
+	}
+}
+
+func TestNumberKey(t *testing.T) {
+	equal := [][]string{
+		{"0", "-0", "0.0", "0e10"},
+		{"1", "1.0", "1e0", "10e-1", "0.1e1"},
+		{"-120", "-1.2e2", "-120.000"},
+		{"123456789012345678901234567890", "1.2345678901234567890123456789e29"},
+	}
+
+	for _, group := range equal {
+		for _, n := range group {
+			if numberKey(json.Number(n)) != numberKey(json.Number(group[0])) {
+				t.Errorf("expected %s to equal %s", n, group[0])
+			}
+		}
+	}
+
+	if numberKey("123456789012345678901") == numberKey("123456789012345678902") {
+		t.Errorf("large integers differing in the last digit should not be equal")
+	}
+}
@@ -0,0 +1,162 @@
+package jason
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The body size limit used when RequestOptions don't set one.
+const DefaultMaxBodyBytes = 1 << 20
+
+// RequestOptions configure ReadRequest.
+type RequestOptions struct {
+	MaxBytes int64 // Maximum size of the decompressed body, DefaultMaxBodyBytes if 0
+
+	// Whether requests without a Content-Type header are read as json.
+	// Requests with another content type than application/json or a +json suffix are always rejected.
+	AllowMissingContentType bool
+}
+
+// RequestError is returned by ReadRequest when a request can't be read.
+// Status is the HTTP status code to respond with: 400, 413 or 415.
+type RequestError struct {
+	Status int
+	Err    error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Reads the body of a request holding a json object. Options may be nil.
+// Checks that the Content-Type is json, decompresses gzip bodies and enforces a size limit on
+// the decompressed body. Returns a *RequestError with the status to respond with if the request
+// can't be read: 415 for other content types or encodings, 413 for bodies that are too large
+// and 400 for bodies that are not a json object.
+// Example:
+//		body, err := jason.ReadRequest(r, nil)
+//		var reqErr *jason.RequestError
+//		if errors.As(err, &reqErr) {
+//			http.Error(w, reqErr.Error(), reqErr.Status)
+//			return
+//		}
+func ReadRequest(r *http.Request, opts *RequestOptions) (*Object, error) {
+	var o RequestOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.MaxBytes <= 0 {
+		o.MaxBytes = DefaultMaxBodyBytes
+	}
+
+	if err := checkContentType(r.Header.Get("Content-Type"), o.AllowMissingContentType); err != nil {
+		return nil, &RequestError{http.StatusUnsupportedMediaType, err}
+	}
+
+	body := io.Reader(r.Body)
+
+	if r.Body == nil {
+		body = http.NoBody
+	}
+
+	switch encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(body)
+
+		if err != nil {
+			return nil, &RequestError{http.StatusBadRequest, fmt.Errorf("invalid gzip body: %w", err)}
+		}
+
+		defer gz.Close()
+		body = gz
+	default:
+		return nil, &RequestError{http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content encoding %q", encoding)}
+	}
+
+	b, err := io.ReadAll(io.LimitReader(body, o.MaxBytes+1))
+
+	if err != nil {
+		return nil, &RequestError{http.StatusBadRequest, fmt.Errorf("reading body: %w", err)}
+	}
+
+	if int64(len(b)) > o.MaxBytes {
+		return nil, &RequestError{http.StatusRequestEntityTooLarge, fmt.Errorf("body exceeds %d bytes", o.MaxBytes)}
+	}
+
+	// Unlike NewObjectFromBytes, reject bodies with data after the object.
+	if err := json.Unmarshal(b, new(json.RawMessage)); err != nil {
+		return nil, &RequestError{http.StatusBadRequest, fmt.Errorf("invalid json body: %w", err)}
+	}
+
+	obj, err := NewObjectFromBytes(b)
+
+	if err != nil {
+		return nil, &RequestError{http.StatusBadRequest, err}
+	}
+
+	obj.exists = true
+
+	return obj, nil
+}
+
+// Checks that a Content-Type header names json.
+func checkContentType(header string, allowMissing bool) error {
+	if header == "" {
+		if allowMissing {
+			return nil
+		}
+
+		return errors.New("missing content type")
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+
+	if err != nil {
+		return fmt.Errorf("invalid content type %q", header)
+	}
+
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return fmt.Errorf("unsupported content type %q", mediaType)
+	}
+
+	return nil
+}
+
+// Writes v as the json body of a response with the given status code, using Encoder.
+// If v can't be encoded, a 500 response is written instead and the error is returned.
+// Pass &o.Value to write an object.
+// Example:
+//		err := jason.WriteResponse(w, http.StatusOK, &o.Value)
+func WriteResponse(w http.ResponseWriter, status int, v *Value) error {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.SetTrailingNewline(true)
+
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(status)
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
@@ -0,0 +1,95 @@
+package jason
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadRequest(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte(`{"name": "anton"}`))
+	gz.Close()
+
+	cases := []struct {
+		name        string
+		body        string
+		contentType string
+		encoding    string
+		opts        *RequestOptions
+		status      int
+	}{
+		{"valid", `{"name": "anton"}`, "application/json", "", nil, 0},
+		{"charset", `{"name": "anton"}`, "application/json; charset=utf-8", "", nil, 0},
+		{"suffix", `{"name": "anton"}`, "application/merge-patch+json", "", nil, 0},
+		{"gzip", gzipped.String(), "application/json", "gzip", nil, 0},
+		{"missing type", `{"name": "anton"}`, "", "", nil, http.StatusUnsupportedMediaType},
+		{"allowed missing type", `{"name": "anton"}`, "", "", &RequestOptions{AllowMissingContentType: true}, 0},
+		{"wrong type", `{"name": "anton"}`, "text/plain", "", nil, http.StatusUnsupportedMediaType},
+		{"wrong encoding", `{"name": "anton"}`, "application/json", "br", nil, http.StatusUnsupportedMediaType},
+		{"bad gzip", `{"name": "anton"}`, "application/json", "gzip", nil, http.StatusBadRequest},
+		{"too large", `{"name": "anton"}`, "application/json", "", &RequestOptions{MaxBytes: 10}, http.StatusRequestEntityTooLarge},
+		{"too large gzip", gzipped.String(), "application/json", "gzip", &RequestOptions{MaxBytes: 10}, http.StatusRequestEntityTooLarge},
+		{"invalid", `{"name": }`, "application/json", "", nil, http.StatusBadRequest},
+		{"trailing", `{"name": "anton"} {}`, "application/json", "", nil, http.StatusBadRequest},
+		{"array", `["anton"]`, "application/json", "", nil, http.StatusBadRequest},
+		{"empty", ``, "application/json", "", nil, http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(c.body))
+		if c.contentType != "" {
+			r.Header.Set("Content-Type", c.contentType)
+		}
+		if c.encoding != "" {
+			r.Header.Set("Content-Encoding", c.encoding)
+		}
+
+		o, err := ReadRequest(r, c.opts)
+
+		if c.status == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", c.name, err)
+				continue
+			}
+
+			if name, _ := o.GetString("name"); name != "anton" {
+				t.Errorf("%s: got name %q", c.name, name)
+			}
+
+			continue
+		}
+
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) || reqErr.Status != c.status {
+			t.Errorf("%s: expected status %d, got %v", c.name, c.status, err)
+		}
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, _ := NewObjectFromBytes([]byte(`{"b": "<tag>", "a": 1}`))
+
+	w := httptest.NewRecorder()
+	err := WriteResponse(w, http.StatusCreated, &o.Value)
+
+	assert.True(err == nil, "writing a response should succeed")
+	assert.True(w.Code == http.StatusCreated, "the status should be written")
+	assert.True(w.Header().Get("Content-Type") == "application/json; charset=utf-8", "the content type should be json")
+	assert.True(w.Body.String() == `{"a":1,"b":"\u003ctag\u003e"}`+"\n", "unexpected body "+w.Body.String())
+
+	invalid := &Value{math.NaN(), true}
+
+	w = httptest.NewRecorder()
+	err = WriteResponse(w, http.StatusOK, invalid)
+
+	assert.True(err != nil && w.Code == http.StatusInternalServerError, "values that can't be encoded should give a 500")
+}
@@ -0,0 +1,308 @@
+package jason
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// IntegerPolicy decides which number literals the integer accessors accept.
+type IntegerPolicy int
+
+const (
+	// IntegralNumbers accepts every number without a fractional part, like 42, 1e3 and 10.0.
+	IntegralNumbers IntegerPolicy = iota
+
+	// StrictIntegers accepts only plain integer literals like 42, as json.Number.Int64 does.
+	StrictIntegers
+)
+
+// IntegerError is returned when a number can't be converted into an integer type,
+// either because it has a fractional part or because it doesn't fit.
+// Err is ErrNotInteger or ErrOutOfRange.
+type IntegerError struct {
+	Path   Path        // Location of the number, empty if unknown
+	Number json.Number // The number as written
+	Type   string      // The Go type, like int8
+	Err    error
+}
+
+func (e *IntegerError) Error() string {
+	location := ""
+	if len(e.Path) > 0 {
+		location = " at " + e.Path.String()
+	}
+
+	if errors.Is(e.Err, ErrNotInteger) {
+		return fmt.Sprintf("number %s%s is not an integer", e.Number, location)
+	}
+
+	return fmt.Sprintf("number %s%s is out of range for %s", e.Number, location, e.Type)
+}
+
+func (e *IntegerError) Unwrap() error {
+	return e.Err
+}
+
+// Attempts to typecast the current value into a signed integer of the given bit size.
+// A bit size of 0 means the size of int. The result always fits into the requested size.
+// Returns ErrBitSize if the bit size is not between 0 and 64, error if the current value is not a json number,
+// or an *IntegerError if the number
+// is not an integer under the policy or overflows.
+// Example:
+//		retries, err := v.Integer(jason.IntegralNumbers, 8)
+func (v *Value) Integer(policy IntegerPolicy, bitSize int) (int64, error) {
+	n, err := v.Number()
+
+	if err != nil {
+		return 0, err
+	}
+
+	bitSize, typeName, err := integerType("int", bitSize)
+
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := integer(n, policy)
+
+	if err == ErrNotNumber {
+		return 0, err
+	}
+
+	if err != nil {
+		return 0, &IntegerError{Number: n, Type: typeName, Err: err}
+	}
+
+	min := new(big.Int).Lsh(big.NewInt(-1), uint(bitSize-1))
+	max := new(big.Int).Sub(new(big.Int).Neg(min), big.NewInt(1))
+
+	if i.Cmp(min) < 0 || i.Cmp(max) > 0 {
+		return 0, &IntegerError{Number: n, Type: typeName, Err: ErrOutOfRange}
+	}
+
+	return i.Int64(), nil
+}
+
+// Attempts to typecast the current value into an unsigned integer of the given bit size.
+// A bit size of 0 means the size of uint. Negative numbers are out of range.
+// Returns ErrBitSize if the bit size is not between 0 and 64, error if the current value is not a json number,
+// or an *IntegerError if the number
+// is not an integer under the policy or overflows.
+// Example:
+//		port, err := v.Unsigned(jason.StrictIntegers, 16)
+func (v *Value) Unsigned(policy IntegerPolicy, bitSize int) (uint64, error) {
+	n, err := v.Number()
+
+	if err != nil {
+		return 0, err
+	}
+
+	bitSize, typeName, err := integerType("uint", bitSize)
+
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := integer(n, policy)
+
+	if err == ErrNotNumber {
+		return 0, err
+	}
+
+	if err != nil {
+		return 0, &IntegerError{Number: n, Type: typeName, Err: err}
+	}
+
+	if i.Sign() < 0 || i.BitLen() > bitSize {
+		return 0, &IntegerError{Number: n, Type: typeName, Err: ErrOutOfRange}
+	}
+
+	return i.Uint64(), nil
+}
+
+// Checks the bit size of an integer type and returns it with the Go type name, like int8.
+// A bit size of 0 means the size of int.
+func integerType(prefix string, bitSize int) (int, string, error) {
+	switch {
+	case bitSize == 0:
+		return strconv.IntSize, prefix, nil
+	case bitSize < 0 || bitSize > 64:
+		return 0, "", ErrBitSize
+	}
+
+	return bitSize, prefix + strconv.Itoa(bitSize), nil
+}
+
+// Parses a number into an integer, accepting exponents and fractional zeros
+// unless the policy is StrictIntegers.
+func integer(n json.Number, policy IntegerPolicy) (*big.Int, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return big.NewInt(i), nil
+	}
+
+	if policy == StrictIntegers {
+		i, ok := new(big.Int).SetString(string(n), 10)
+
+		if !ok {
+			return nil, ErrNotInteger
+		}
+
+		return i, nil
+	}
+
+	return (&Value{n, true}).BigInt()
+}
+
+// Attempts to typecast the current value into an int.
+// Integral numbers like 1e3 and 10.0 are accepted.
+// Returns error if the current value is not a json number, or an *IntegerError if it is not an integer or overflows.
+// Example:
+//		count, err := v.Int()
+func (v *Value) Int() (int, error) {
+	i, err := v.Integer(IntegralNumbers, 0)
+	return int(i), err
+}
+
+// Attempts to typecast the current value into an int8.
+// Returns error like Int.
+func (v *Value) Int8() (int8, error) {
+	i, err := v.Integer(IntegralNumbers, 8)
+	return int8(i), err
+}
+
+// Attempts to typecast the current value into an int16.
+// Returns error like Int.
+func (v *Value) Int16() (int16, error) {
+	i, err := v.Integer(IntegralNumbers, 16)
+	return int16(i), err
+}
+
+// Attempts to typecast the current value into an int32.
+// Returns error like Int.
+func (v *Value) Int32() (int32, error) {
+	i, err := v.Integer(IntegralNumbers, 32)
+	return int32(i), err
+}
+
+// Attempts to typecast the current value into a uint.
+// Returns error like Int, and negative numbers are out of range.
+func (v *Value) Uint() (uint, error) {
+	i, err := v.Unsigned(IntegralNumbers, 0)
+	return uint(i), err
+}
+
+// Attempts to typecast the current value into a uint8.
+// Returns error like Uint.
+func (v *Value) Uint8() (uint8, error) {
+	i, err := v.Unsigned(IntegralNumbers, 8)
+	return uint8(i), err
+}
+
+// Attempts to typecast the current value into a uint16.
+// Returns error like Uint.
+func (v *Value) Uint16() (uint16, error) {
+	i, err := v.Unsigned(IntegralNumbers, 16)
+	return uint16(i), err
+}
+
+// Attempts to typecast the current value into a uint32.
+// Returns error like Uint.
+func (v *Value) Uint32() (uint32, error) {
+	i, err := v.Unsigned(IntegralNumbers, 32)
+	return uint32(i), err
+}
+
+// Gets the value at key path and attempts to typecast the value into a signed integer of the given bit size.
+// Returns error if the value is not a json number, or an *IntegerError naming the key path
+// if it is not an integer under the policy or overflows.
+// Example:
+//		retries, err := GetInteger(jason.StrictIntegers, 8, "config", "retries")
+func (v *Object) GetInteger(policy IntegerPolicy, bitSize int, keys ...string) (int64, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := child.Integer(policy, bitSize)
+
+	return i, withPath(err, keyPath(keys))
+}
+
+// Gets the value at key path and attempts to typecast the value into an unsigned integer of the given bit size.
+// Returns error like GetInteger, and negative numbers are out of range.
+// Example:
+//		port, err := GetUnsigned(jason.StrictIntegers, 16, "server", "port")
+func (v *Object) GetUnsigned(policy IntegerPolicy, bitSize int, keys ...string) (uint64, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := child.Unsigned(policy, bitSize)
+
+	return i, withPath(err, keyPath(keys))
+}
+
+// Gets the value at key path and attempts to typecast the value into an int.
+// Integral numbers like 1e3 and 10.0 are accepted.
+// Returns error like GetInteger.
+// Example:
+//		count, err := GetInt("stats", "count")
+func (v *Object) GetInt(keys ...string) (int, error) {
+	i, err := v.GetInteger(IntegralNumbers, 0, keys...)
+	return int(i), err
+}
+
+// Gets the value at key path and attempts to typecast the value into an int8.
+// Returns error like GetInteger.
+func (v *Object) GetInt8(keys ...string) (int8, error) {
+	i, err := v.GetInteger(IntegralNumbers, 8, keys...)
+	return int8(i), err
+}
+
+// Gets the value at key path and attempts to typecast the value into an int16.
+// Returns error like GetInteger.
+func (v *Object) GetInt16(keys ...string) (int16, error) {
+	i, err := v.GetInteger(IntegralNumbers, 16, keys...)
+	return int16(i), err
+}
+
+// Gets the value at key path and attempts to typecast the value into an int32.
+// Returns error like GetInteger.
+func (v *Object) GetInt32(keys ...string) (int32, error) {
+	i, err := v.GetInteger(IntegralNumbers, 32, keys...)
+	return int32(i), err
+}
+
+// Gets the value at key path and attempts to typecast the value into a uint.
+// Returns error like GetUnsigned.
+func (v *Object) GetUint(keys ...string) (uint, error) {
+	i, err := v.GetUnsigned(IntegralNumbers, 0, keys...)
+	return uint(i), err
+}
+
+// Gets the value at key path and attempts to typecast the value into a uint8.
+// Returns error like GetUnsigned.
+func (v *Object) GetUint8(keys ...string) (uint8, error) {
+	i, err := v.GetUnsigned(IntegralNumbers, 8, keys...)
+	return uint8(i), err
+}
+
+// Gets the value at key path and attempts to typecast the value into a uint16.
+// Returns error like GetUnsigned.
+func (v *Object) GetUint16(keys ...string) (uint16, error) {
+	i, err := v.GetUnsigned(IntegralNumbers, 16, keys...)
+	return uint16(i), err
+}
+
+// Gets the value at key path and attempts to typecast the value into a uint32.
+// Returns error like GetUnsigned.
+func (v *Object) GetUint32(keys ...string) (uint32, error) {
+	i, err := v.GetUnsigned(IntegralNumbers, 32, keys...)
+	return uint32(i), err
+}
@@ -0,0 +1,70 @@
+package jason
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInteger(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{"plain": 42, "exp": 1e3, "float": 10.0, "fraction": 2.5, "negative": -129, "port": 65536, "name": "x", "config": {"retries": 300}}`))
+	assert.True(err == nil, "failed to parse object")
+
+	for _, key := range []string{"plain", "exp", "float"} {
+		_, err := o.GetInteger(IntegralNumbers, 64, key)
+		assert.True(err == nil, key+" should be accepted as an integral number")
+	}
+
+	i, err := o.GetInt("exp")
+	assert.True(err == nil && i == 1000, "GetInt should accept exponent notation")
+
+	u, err := o.GetUint16("float")
+	assert.True(err == nil && u == 10, "GetUint16 should accept integral floats")
+
+	_, err = o.GetInteger(StrictIntegers, 64, "exp")
+	assert.True(errors.Is(err, ErrNotInteger), "strict policy should reject exponents")
+
+	_, err = o.GetInteger(StrictIntegers, 64, "float")
+	assert.True(errors.Is(err, ErrNotInteger), "strict policy should reject integral floats")
+
+	_, err = o.GetInt32("fraction")
+	assert.True(errors.Is(err, ErrNotInteger), "fractions should be rejected")
+
+	_, err = o.GetInt8("negative")
+	assert.True(errors.Is(err, ErrOutOfRange), "-129 should overflow int8")
+
+	n, err := o.GetInt16("negative")
+	assert.True(err == nil && n == -129, "-129 should fit into int16")
+
+	_, err = o.GetUint8("negative")
+	assert.True(errors.Is(err, ErrOutOfRange), "negative numbers should be out of range for unsigned integers")
+
+	_, err = o.GetUnsigned(StrictIntegers, 16, "port")
+	assert.True(errors.Is(err, ErrOutOfRange), "65536 should overflow uint16")
+
+	_, err = o.GetInt("name")
+	assert.True(err == ErrNotNumber, "strings should return ErrNotNumber")
+
+	_, err = o.GetInt8("config", "retries")
+	var integerErr *IntegerError
+	assert.True(errors.As(err, &integerErr), "overflow should return an *IntegerError")
+	assert.True(integerErr.Path.String() == "$.config.retries" && integerErr.Number == "300" && integerErr.Type == "int8", "IntegerError should name the path, number and type")
+	assert.True(err.Error() == "number 300 at $.config.retries is out of range for int8", "unexpected message: "+err.Error())
+
+	v, _ := NewValueFromBytes([]byte(`1.5`))
+	_, err = v.Uint()
+	assert.True(err.Error() == "number 1.5 is not an integer", "unexpected message: "+err.Error())
+
+	huge, _ := NewValueFromBytes([]byte(`18446744073709551617`))
+	for _, bitSize := range []int{128, 65, -1} {
+		_, err = huge.Integer(IntegralNumbers, bitSize)
+		assert.True(err == ErrBitSize, "Integer should reject bit sizes outside 0 to 64")
+
+		_, err = huge.Unsigned(IntegralNumbers, bitSize)
+		assert.True(err == ErrBitSize, "Unsigned should reject bit sizes outside 0 to 64")
+	}
+
+	_, err = o.GetInteger(IntegralNumbers, -1, "plain")
+	assert.True(err == ErrBitSize, "GetInteger should reject negative bit sizes")
+}
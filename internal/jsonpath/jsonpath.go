@@ -0,0 +1,1059 @@
+// Package jsonpath evaluates JSONPath queries over the data of jason values.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Query is a compiled JSONPath query (RFC 9535).
+// It supports child and descendant segments, name, wildcard, index, slice and
+// filter selectors, and the functions length(), count(), match(), search() and value().
+// Example:
+//		q, err := jsonpath.Compile("$.friends[?@.age > 30].name")
+//		for _, node := range q.Select(v.Interface()) {
+//			log.Println(jason.Path(node.Path), node.Data)
+//		}
+type Query struct {
+	source   string
+	segments []pathSegment
+}
+
+// Node is a value selected by a query, together with its location.
+// Each path element is either a string, naming an object key, or an int, indexing an array.
+type Node struct {
+	Path []interface{}
+	Data interface{}
+}
+
+// Compiles a JSONPath query. Returns an error if the query is malformed.
+func Compile(expr string) (*Query, error) {
+	p := &pathParser{src: expr}
+
+	p.skipSpace()
+	if !p.consume("$") {
+		return nil, p.errorf("query must start with $")
+	}
+
+	segments, err := p.parseSegments()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+
+	return &Query{source: expr, segments: segments}, nil
+}
+
+// Returns the source of the query.
+func (q *Query) String() string {
+	return q.source
+}
+
+// Returns all nodes the query selects from root, in document order.
+// Root is data as returned by jason's Value.Interface().
+// Object members are visited in sorted key order.
+func (q *Query) Select(root interface{}) []Node {
+	nodes := evalSegments(q.segments, []pathNode{{data: root}}, root)
+
+	selected := make([]Node, len(nodes))
+	for i, n := range nodes {
+		selected[i] = Node{Path: n.path, Data: n.data}
+	}
+
+	return selected
+}
+
+// pathNode is a node visited while evaluating a query.
+type pathNode struct {
+	path []interface{}
+	data interface{}
+}
+
+// Returns a copy of path with one more element appended.
+// The copy never shares its backing array with path.
+func appendPath(path []interface{}, element interface{}) []interface{} {
+	child := make([]interface{}, len(path), len(path)+1)
+	copy(child, path)
+
+	return append(child, element)
+}
+
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+type pathSelector interface {
+	selectFrom(n pathNode, root interface{}, out []pathNode) []pathNode
+}
+
+func evalSegments(segments []pathSegment, nodes []pathNode, root interface{}) []pathNode {
+	for _, segment := range segments {
+		var next []pathNode
+
+		for _, n := range nodes {
+			if segment.descendant {
+				for _, d := range descendants(n, nil) {
+					for _, s := range segment.selectors {
+						next = s.selectFrom(d, root, next)
+					}
+				}
+			} else {
+				for _, s := range segment.selectors {
+					next = s.selectFrom(n, root, next)
+				}
+			}
+		}
+
+		nodes = next
+	}
+
+	return nodes
+}
+
+// Returns n and all nodes below it, in document order.
+func descendants(n pathNode, out []pathNode) []pathNode {
+	out = append(out, n)
+
+	for _, child := range children(n) {
+		out = descendants(child, out)
+	}
+
+	return out
+}
+
+func children(n pathNode) []pathNode {
+	switch data := n.data.(type) {
+	case []interface{}:
+		result := make([]pathNode, len(data))
+		for i, element := range data {
+			result[i] = pathNode{path: appendPath(n.path, i), data: element}
+		}
+		return result
+	case map[string]interface{}:
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		result := make([]pathNode, len(keys))
+		for i, key := range keys {
+			result[i] = pathNode{path: appendPath(n.path, key), data: data[key]}
+		}
+		return result
+	}
+
+	return nil
+}
+
+type nameSelector string
+
+func (s nameSelector) selectFrom(n pathNode, root interface{}, out []pathNode) []pathNode {
+	if m, ok := n.data.(map[string]interface{}); ok {
+		if child, ok := m[string(s)]; ok {
+			out = append(out, pathNode{path: appendPath(n.path, string(s)), data: child})
+		}
+	}
+
+	return out
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectFrom(n pathNode, root interface{}, out []pathNode) []pathNode {
+	return append(out, children(n)...)
+}
+
+type indexSelector int
+
+func (s indexSelector) selectFrom(n pathNode, root interface{}, out []pathNode) []pathNode {
+	if array, ok := n.data.([]interface{}); ok {
+		index := int(s)
+		if index < 0 {
+			index += len(array)
+		}
+
+		if index >= 0 && index < len(array) {
+			out = append(out, pathNode{path: appendPath(n.path, index), data: array[index]})
+		}
+	}
+
+	return out
+}
+
+type sliceSelector struct {
+	start, end, step *int
+}
+
+func (s sliceSelector) selectFrom(n pathNode, root interface{}, out []pathNode) []pathNode {
+	array, ok := n.data.([]interface{})
+
+	if !ok {
+		return out
+	}
+
+	length := len(array)
+	step := 1
+	if s.step != nil {
+		step = *s.step
+	}
+
+	if step == 0 {
+		return out
+	}
+
+	normalize := func(i int) int {
+		if i < 0 {
+			return length + i
+		}
+		return i
+	}
+
+	clamp := func(i, low, high int) int {
+		if i < low {
+			return low
+		}
+		if i > high {
+			return high
+		}
+		return i
+	}
+
+	if step > 0 {
+		start, end := 0, length
+		if s.start != nil {
+			start = clamp(normalize(*s.start), 0, length)
+		}
+		if s.end != nil {
+			end = clamp(normalize(*s.end), 0, length)
+		}
+
+		for i := start; i < end; i += step {
+			out = append(out, pathNode{path: appendPath(n.path, i), data: array[i]})
+		}
+	} else {
+		start, end := length-1, -1
+		if s.start != nil {
+			start = clamp(normalize(*s.start), -1, length-1)
+		}
+		if s.end != nil {
+			end = clamp(normalize(*s.end), -1, length-1)
+		}
+
+		for i := start; i > end; i += step {
+			out = append(out, pathNode{path: appendPath(n.path, i), data: array[i]})
+		}
+	}
+
+	return out
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s filterSelector) selectFrom(n pathNode, root interface{}, out []pathNode) []pathNode {
+	for _, child := range children(n) {
+		if s.expr.test(child.data, root) {
+			out = append(out, child)
+		}
+	}
+
+	return out
+}
+
+// filterExpr is a logical expression inside a filter selector.
+type filterExpr interface {
+	test(current, root interface{}) bool
+}
+
+type orExpr []filterExpr
+
+func (e orExpr) test(current, root interface{}) bool {
+	for _, term := range e {
+		if term.test(current, root) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []filterExpr
+
+func (e andExpr) test(current, root interface{}) bool {
+	for _, term := range e {
+		if !term.test(current, root) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e notExpr) test(current, root interface{}) bool {
+	return !e.expr.test(current, root)
+}
+
+// existsExpr tests that a query selects at least one node.
+type existsExpr struct {
+	query *queryOperand
+}
+
+func (e existsExpr) test(current, root interface{}) bool {
+	return len(e.query.nodes(current, root)) > 0
+}
+
+// logicalFunctionExpr tests the result of match() or search().
+type logicalFunctionExpr struct {
+	call *functionOperand
+}
+
+func (e logicalFunctionExpr) test(current, root interface{}) bool {
+	result := e.call.value(current, root)
+	b, ok := result.data.(bool)
+	return !result.nothing && ok && b
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right operand
+}
+
+func (e comparisonExpr) test(current, root interface{}) bool {
+	left := e.left.value(current, root)
+	right := e.right.value(current, root)
+
+	switch e.op {
+	case "==":
+		return compareEqual(left, right)
+	case "!=":
+		return !compareEqual(left, right)
+	case "<":
+		return compareLess(left, right)
+	case ">":
+		return compareLess(right, left)
+	case "<=":
+		return compareLess(left, right) || compareEqual(left, right)
+	case ">=":
+		return compareLess(right, left) || compareEqual(left, right)
+	}
+
+	return false
+}
+
+// operandValue is the value of an operand. Nothing means that a query selected no single node.
+type operandValue struct {
+	data    interface{}
+	nothing bool
+}
+
+type operand interface {
+	value(current, root interface{}) operandValue
+}
+
+type literalOperand struct {
+	data interface{}
+}
+
+func (o literalOperand) value(current, root interface{}) operandValue {
+	return operandValue{data: o.data}
+}
+
+type queryOperand struct {
+	relative bool
+	segments []pathSegment
+}
+
+func (o *queryOperand) nodes(current, root interface{}) []pathNode {
+	start := root
+	if o.relative {
+		start = current
+	}
+
+	return evalSegments(o.segments, []pathNode{{data: start}}, root)
+}
+
+func (o *queryOperand) value(current, root interface{}) operandValue {
+	nodes := o.nodes(current, root)
+
+	if len(nodes) != 1 {
+		return operandValue{nothing: true}
+	}
+
+	return operandValue{data: nodes[0].data}
+}
+
+// Reports whether the query selects at most one node, as required in comparisons.
+func (o *queryOperand) singular() bool {
+	for _, segment := range o.segments {
+		if segment.descendant || len(segment.selectors) != 1 {
+			return false
+		}
+
+		switch segment.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+type functionOperand struct {
+	name string
+	args []interface{} // operand or *queryOperand for node list arguments
+}
+
+func (f *functionOperand) value(current, root interface{}) operandValue {
+	argValue := func(i int) operandValue {
+		switch arg := f.args[i].(type) {
+		case *queryOperand:
+			return arg.value(current, root)
+		case operand:
+			return arg.value(current, root)
+		}
+		return operandValue{nothing: true}
+	}
+
+	switch f.name {
+	case "length":
+		v := argValue(0)
+
+		switch data := v.data.(type) {
+		case string:
+			if !v.nothing {
+				return operandValue{data: json.Number(strconv.Itoa(utf8.RuneCountInString(data)))}
+			}
+		case []interface{}:
+			return operandValue{data: json.Number(strconv.Itoa(len(data)))}
+		case map[string]interface{}:
+			return operandValue{data: json.Number(strconv.Itoa(len(data)))}
+		}
+
+		return operandValue{nothing: true}
+	case "count":
+		nodes := f.args[0].(*queryOperand).nodes(current, root)
+		return operandValue{data: json.Number(strconv.Itoa(len(nodes)))}
+	case "value":
+		return f.args[0].(*queryOperand).value(current, root)
+	case "match", "search":
+		s, ok1 := argValue(0).data.(string)
+		pattern, ok2 := argValue(1).data.(string)
+
+		if !ok1 || !ok2 {
+			return operandValue{data: false}
+		}
+
+		if f.name == "match" {
+			pattern = "^(?:" + pattern + ")$"
+		}
+
+		re, err := regexp.Compile(pattern)
+
+		if err != nil {
+			return operandValue{data: false}
+		}
+
+		return operandValue{data: re.MatchString(s)}
+	}
+
+	return operandValue{nothing: true}
+}
+
+func compareEqual(a, b operandValue) bool {
+	if a.nothing || b.nothing {
+		return a.nothing && b.nothing
+	}
+
+	return equal(a.data, b.data)
+}
+
+// Reports whether two values are structurally equal, comparing numbers by value.
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case json.Number:
+		b, ok := b.(json.Number)
+		return ok && compareNumbers(a, b) == 0
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, element := range a {
+			other, ok := b[key]
+			if !ok || !equal(element, other) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// Compares two numbers, returning -1, 0 or +1. Invalid numbers compare as 2.
+func compareNumbers(x, y json.Number) int {
+	fx, _, errx := big.ParseFloat(string(x), 10, 256, big.ToNearestEven)
+	fy, _, erry := big.ParseFloat(string(y), 10, 256, big.ToNearestEven)
+
+	if errx != nil || erry != nil {
+		return 2
+	}
+
+	return fx.Cmp(fy)
+}
+
+func compareLess(a, b operandValue) bool {
+	if a.nothing || b.nothing {
+		return false
+	}
+
+	switch x := a.data.(type) {
+	case json.Number:
+		y, ok := b.data.(json.Number)
+		if !ok {
+			return false
+		}
+
+		return compareNumbers(x, y) < 0
+	case string:
+		y, ok := b.data.(string)
+		return ok && x < y
+	}
+
+	return false
+}
+
+// pathParser is a recursive descent parser for JSONPath queries.
+type pathParser struct {
+	src string
+	pos int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("jason: invalid jsonpath %q at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\n\r", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *pathParser) peek(s string) bool {
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+func (p *pathParser) consume(s string) bool {
+	if p.peek(s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) parseSegments() ([]pathSegment, error) {
+	var segments []pathSegment
+
+	for {
+		start := p.pos
+		p.skipSpace()
+
+		switch {
+		case p.consume(".."):
+			segment := pathSegment{descendant: true}
+
+			if p.peek("[") {
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = selectors
+			} else if p.consume("*") {
+				segment.selectors = []pathSelector{wildcardSelector{}}
+			} else {
+				name := p.parseName()
+				if name == "" {
+					return nil, p.errorf("expected name after ..")
+				}
+				segment.selectors = []pathSelector{nameSelector(name)}
+			}
+
+			segments = append(segments, segment)
+		case p.consume("."):
+			if p.consume("*") {
+				segments = append(segments, pathSegment{selectors: []pathSelector{wildcardSelector{}}})
+				continue
+			}
+
+			name := p.parseName()
+			if name == "" {
+				return nil, p.errorf("expected name after .")
+			}
+
+			segments = append(segments, pathSegment{selectors: []pathSelector{nameSelector(name)}})
+		case p.peek("["):
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+
+			segments = append(segments, pathSegment{selectors: selectors})
+		default:
+			p.pos = start
+			return segments, nil
+		}
+	}
+}
+
+func (p *pathParser) parseName() string {
+	start := p.pos
+
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r >= 0x80 ||
+			(p.pos > start && r >= '0' && r <= '9') {
+			p.pos += size
+			continue
+		}
+
+		break
+	}
+
+	return p.src[start:p.pos]
+}
+
+func (p *pathParser) parseBracket() ([]pathSelector, error) {
+	p.consume("[")
+
+	var selectors []pathSelector
+
+	for {
+		p.skipSpace()
+
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+
+		selectors = append(selectors, selector)
+
+		p.skipSpace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+
+		if !p.consume(",") {
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *pathParser) parseSelector() (pathSelector, error) {
+	switch {
+	case p.consume("*"):
+		return wildcardSelector{}, nil
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector(s), nil
+	case p.consume("?"):
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{expr: expr}, nil
+	}
+
+	var values [3]*int
+	part := 0
+
+	for {
+		p.skipSpace()
+
+		if n, ok := p.parseInt(); ok {
+			values[part] = &n
+		}
+
+		p.skipSpace()
+
+		if part < 2 && p.consume(":") {
+			part++
+			continue
+		}
+
+		break
+	}
+
+	if part == 0 {
+		if values[0] == nil {
+			return nil, p.errorf("expected selector")
+		}
+
+		return indexSelector(*values[0]), nil
+	}
+
+	return sliceSelector{start: values[0], end: values[1], step: values[2]}, nil
+}
+
+func (p *pathParser) parseInt() (int, bool) {
+	start := p.pos
+
+	if p.pos < len(p.src) && p.src[p.pos] == '-' {
+		p.pos++
+	}
+
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+
+	n, err := strconv.Atoi(p.src[start:p.pos])
+
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+
+	return n, true
+}
+
+func (p *pathParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+
+	var b strings.Builder
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			if p.pos+1 >= len(p.src) {
+				return "", p.errorf("unterminated string")
+			}
+
+			escaped := p.src[p.pos+1]
+			p.pos += 2
+
+			switch escaped {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '/', '\\', '\'', '"':
+				b.WriteByte(escaped)
+			case 'u':
+				if p.pos+4 > len(p.src) {
+					return "", p.errorf("invalid unicode escape")
+				}
+
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+
+				p.pos += 4
+				b.WriteRune(rune(r))
+			default:
+				return "", p.errorf("invalid escape \\%c", escaped)
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *pathParser) parseOr() (filterExpr, error) {
+	var terms orExpr
+
+	for {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		terms = append(terms, term)
+
+		p.skipSpace()
+		if !p.consume("||") {
+			break
+		}
+	}
+
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+
+	return terms, nil
+}
+
+func (p *pathParser) parseAnd() (filterExpr, error) {
+	var terms andExpr
+
+	for {
+		term, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+
+		terms = append(terms, term)
+
+		p.skipSpace()
+		if !p.consume("&&") {
+			break
+		}
+	}
+
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+
+	return terms, nil
+}
+
+func (p *pathParser) parseBasic() (filterExpr, error) {
+	p.skipSpace()
+
+	if p.consume("!") {
+		p.skipSpace()
+
+		if p.consume("(") {
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			p.skipSpace()
+			if !p.consume(")") {
+				return nil, p.errorf("expected )")
+			}
+
+			return notExpr{expr}, nil
+		}
+
+		left, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		test, err := p.testExpr(left)
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{test}, nil
+	}
+
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.checkComparable(left); err != nil {
+			return nil, err
+		}
+
+		if err := p.checkComparable(right); err != nil {
+			return nil, err
+		}
+
+		return comparisonExpr{op: op, left: left, right: right}, nil
+	}
+
+	return p.testExpr(left)
+}
+
+// Converts an operand used on its own into a test.
+func (p *pathParser) testExpr(o operand) (filterExpr, error) {
+	switch o := o.(type) {
+	case *queryOperand:
+		return existsExpr{o}, nil
+	case *functionOperand:
+		if o.name == "match" || o.name == "search" {
+			return logicalFunctionExpr{o}, nil
+		}
+	}
+
+	return nil, p.errorf("expression is not a test")
+}
+
+func (p *pathParser) checkComparable(o operand) error {
+	switch o := o.(type) {
+	case *queryOperand:
+		if !o.singular() {
+			return p.errorf("only singular queries can be compared")
+		}
+	case *functionOperand:
+		if o.name == "match" || o.name == "search" {
+			return p.errorf("%s() can not be compared", o.name)
+		}
+	}
+
+	return nil
+}
+
+func (p *pathParser) parseOperand() (operand, error) {
+	p.skipSpace()
+
+	switch {
+	case p.peek("@") || p.peek("$"):
+		relative := p.src[p.pos] == '@'
+		p.pos++
+
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+
+		return &queryOperand{relative: relative, segments: segments}, nil
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literalOperand{s}, nil
+	case p.consume("true"):
+		return literalOperand{true}, nil
+	case p.consume("false"):
+		return literalOperand{false}, nil
+	case p.consume("null"):
+		return literalOperand{nil}, nil
+	}
+
+	if p.pos < len(p.src) && (p.src[p.pos] == '-' || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+		start := p.pos
+		p.pos++
+
+		for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+
+		literal := p.src[start:p.pos]
+		if !json.Valid([]byte(literal)) {
+			return nil, p.errorf("invalid number %q", literal)
+		}
+
+		return literalOperand{json.Number(literal)}, nil
+	}
+
+	name := p.parseName()
+
+	if name == "" || !p.consume("(") {
+		return nil, p.errorf("expected operand")
+	}
+
+	arity := map[string]int{"length": 1, "count": 1, "value": 1, "match": 2, "search": 2}[name]
+
+	if arity == 0 {
+		return nil, p.errorf("unknown function %s()", name)
+	}
+
+	call := &functionOperand{name: name}
+
+	for i := 0; i < arity; i++ {
+		if i > 0 {
+			p.skipSpace()
+			if !p.consume(",") {
+				return nil, p.errorf("%s() expects %d arguments", name, arity)
+			}
+		}
+
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		query, isQuery := arg.(*queryOperand)
+
+		switch {
+		case (name == "count" || name == "value") && !isQuery:
+			return nil, p.errorf("%s() expects a query", name)
+		case isQuery && name != "count" && name != "value" && !query.singular():
+			return nil, p.errorf("%s() expects a singular query", name)
+		}
+
+		call.args = append(call.args, arg)
+	}
+
+	p.skipSpace()
+	if !p.consume(")") {
+		return nil, p.errorf("%s() expects %d arguments", name, arity)
+	}
+
+	return call, nil
+}
@@ -0,0 +1,135 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+)
+
+type Assert struct {
+	T *testing.T
+}
+
+func NewAssert(t *testing.T) *Assert {
+	return &Assert{
+		T: t,
+	}
+}
+
+func (assert *Assert) True(value bool, message string) {
+	if value == false {
+		log.Panicln("Assert: ", message)
+	}
+}
+
+const storeJSON = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	},
+	"expensive": 10
+}`
+
+// Decodes a document into the data jason values hold.
+func decode(t *testing.T, s string) interface{} {
+	d := json.NewDecoder(bytes.NewReader([]byte(s)))
+	d.UseNumber()
+
+	var data interface{}
+	NewAssert(t).True(d.Decode(&data) == nil, "failed to parse "+s)
+
+	return data
+}
+
+func TestQuery(t *testing.T) {
+	assert := NewAssert(t)
+
+	data := decode(t, storeJSON)
+
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{`$.store.book[*].author`, `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`},
+		{`$..author`, `["Nigel Rees","Evelyn Waugh","Herman Melville","J. R. R. Tolkien"]`},
+		{`$.store.*.color`, `["red"]`},
+		{`$.store..price`, `[399,8.95,12.99,8.99,22.99]`},
+		{`$..book[2].title`, `["Moby Dick"]`},
+		{`$..book[-1].title`, `["The Lord of the Rings"]`},
+		{`$..book[0,1].title`, `["Sayings of the Century","Sword of Honour"]`},
+		{`$..book[:2].title`, `["Sayings of the Century","Sword of Honour"]`},
+		{`$..book[::-2].title`, `["The Lord of the Rings","Sword of Honour"]`},
+		{`$..book[?@.isbn].title`, `["Moby Dick","The Lord of the Rings"]`},
+		{`$..book[?@.price<10].title`, `["Sayings of the Century","Moby Dick"]`},
+		{`$..book[?@.price > $.expensive && @.category == 'fiction'].title`, `["Sword of Honour","The Lord of the Rings"]`},
+		{`$..book[?!@.isbn || @.price == 22.990].title`, `["Sayings of the Century","Sword of Honour","The Lord of the Rings"]`},
+		{`$..book[?match(@.author, 'H.*')].author`, `["Herman Melville"]`},
+		{`$..book[?search(@.title, 'of the')].title`, `["Sayings of the Century","The Lord of the Rings"]`},
+		{`$..book[?length(@.title) == 9].title`, `["Moby Dick"]`},
+		{`$.store[?count(@.*) == 2].color`, `["red"]`},
+		{`$['store']["bicycle"]['color']`, `["red"]`},
+		{`$.missing`, `[]`},
+		{`$.expensive`, `[10]`},
+	}
+
+	for _, c := range cases {
+		q, err := Compile(c.query)
+		assert.True(err == nil, fmt.Sprintf("%s: %v", c.query, err))
+
+		selected := []interface{}{}
+		for _, node := range q.Select(data) {
+			selected = append(selected, node.Data)
+		}
+
+		b, _ := json.Marshal(selected)
+		assert.True(string(b) == c.expected, fmt.Sprintf("%s: got %s, expected %s", c.query, b, c.expected))
+	}
+}
+
+func TestQueryPaths(t *testing.T) {
+	assert := NewAssert(t)
+
+	q, err := Compile(`$..book[?@.price > 20]['title']`)
+	assert.True(err == nil, "failed to compile")
+
+	nodes := q.Select(decode(t, storeJSON))
+	assert.True(len(nodes) == 1, "expected one node")
+
+	path := nodes[0].Path
+	assert.True(len(path) == 4 && path[0] == "store" && path[1] == "book" && path[2] == 3 && path[3] == "title", fmt.Sprintf("unexpected path %v", path))
+	assert.True(nodes[0].Data == "The Lord of the Rings", "nodes should hold the selected data")
+	assert.True(q.String() == `$..book[?@.price > 20]['title']`, "String should return the source")
+}
+
+func TestCompileErrors(t *testing.T) {
+	assert := NewAssert(t)
+
+	invalid := []string{
+		``,
+		`store`,
+		`$.`,
+		`$[`,
+		`$['a`,
+		`$[?@.a == ]`,
+		`$[?@..a == 1]`,
+		`$[?@.* == 1]`,
+		`$[?unknown(@.a)]`,
+		`$[?length(@.a)]`,
+		`$[?count(1) == 1]`,
+		`$[?1]`,
+		`$ x`,
+	}
+
+	for _, query := range invalid {
+		_, err := Compile(query)
+		assert.True(err != nil && strings.Contains(err.Error(), "invalid jsonpath"), fmt.Sprintf("%q should return an invalid jsonpath error, got %v", query, err))
+	}
+}
@@ -0,0 +1,62 @@
+package jason
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Returns an iterator over the keys and values of the object, in unspecified order.
+// Unlike Map, wrapper values are only created for the entries that are visited,
+// so breaking out of the loop early skips the rest.
+// Example:
+//		for key, value := range person.All() {
+//			...
+//		}
+func (v *Object) All() iter.Seq2[string, *Value] {
+	return func(yield func(string, *Value) bool) {
+		data, _ := v.data.(map[string]interface{})
+
+		for key, element := range data {
+			if !yield(key, &Value{element, true}) {
+				return
+			}
+		}
+	}
+}
+
+// Returns an iterator over the keys and values of the object, sorted by key.
+// Example:
+//		for key, value := range person.Sorted() {
+//			...
+//		}
+func (v *Object) Sorted() iter.Seq2[string, *Value] {
+	return func(yield func(string, *Value) bool) {
+		data, _ := v.data.(map[string]interface{})
+
+		for _, key := range slices.Sorted(maps.Keys(data)) {
+			if !yield(key, &Value{data[key], true}) {
+				return
+			}
+		}
+	}
+}
+
+// Returns an iterator over the indices and elements of the array.
+// The iterator yields nothing if the current value is not a json array.
+// Unlike Array, wrapper values are only created for the elements that are visited.
+// Example:
+//		for i, friend := range friendsValue.Elements() {
+//			...
+//		}
+func (v *Value) Elements() iter.Seq2[int, *Value] {
+	return func(yield func(int, *Value) bool) {
+		data, _ := v.data.([]interface{})
+
+		for i, element := range data {
+			if !yield(i, &Value{element, true}) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package jason
+
+import (
+	"testing"
+)
+
+func TestIterators(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{"c": 3, "a": 1, "b": 2, "list": ["x", "y", "z"]}`))
+	assert.True(err == nil, "failed to parse object")
+
+	seen := make(map[string]bool)
+	for key, value := range o.All() {
+		assert.True(value.exists, "iterated values should exist")
+		seen[key] = true
+	}
+	assert.True(len(seen) == 4, "All should visit every key")
+
+	count := 0
+	for range o.All() {
+		count++
+		break
+	}
+	assert.True(count == 1, "All should stop early")
+
+	var keys []string
+	for key := range o.Sorted() {
+		keys = append(keys, key)
+	}
+	assert.True(len(keys) == 4 && keys[0] == "a" && keys[1] == "b" && keys[2] == "c" && keys[3] == "list", "Sorted should visit keys in order")
+
+	for key, value := range o.Sorted() {
+		n, err := value.Int64()
+		assert.True(key == "a" && err == nil && n == 1, "Sorted should yield the value of the key")
+		break
+	}
+
+	list, _ := o.GetValue("list")
+
+	var elements []string
+	for i, element := range list.Elements() {
+		s, _ := element.String()
+		elements = append(elements, s)
+
+		if i == 1 {
+			break
+		}
+	}
+	assert.True(len(elements) == 2 && elements[0] == "x" && elements[1] == "y", "Elements should visit elements in order and stop early")
+
+	name, _ := o.GetValue("a")
+	for range name.Elements() {
+		assert.True(false, "Elements should yield nothing for non-arrays")
+	}
+}
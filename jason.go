@@ -52,6 +52,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"strconv"
 )
 
 // Error values returned when validation functions fail
@@ -107,20 +109,26 @@ func (v *Object) Map() map[string]*Value {
 // Creates a new value from an io.reader.
 // Returns an error if the reader does not contain valid json.
 // Useful for parsing the body of a net/http response.
+// Pass JSONC or JSON5 to accept comments and other relaxed syntax, like in config files.
 // Example: NewFromReader(res.Body)
-func NewValueFromReader(reader io.Reader) (*Value, error) {
+func NewValueFromReader(reader io.Reader, options ...ParseOption) (*Value, error) {
+	if o := parseOptionsOf(options); o.syntax != StrictJSON {
+		return newRelaxedValue(reader, o)
+	}
+
 	j := new(Value)
 	d := json.NewDecoder(reader)
 	d.UseNumber()
 	err := d.Decode(&j.data)
+	j.exists = err == nil // A parsed null exists, unlike a missing value
 	return j, err
 }
 
 // Creates a new value from bytes.
 // Returns an error if the bytes are not valid json.
-func NewValueFromBytes(b []byte) (*Value, error) {
+func NewValueFromBytes(b []byte, options ...ParseOption) (*Value, error) {
 	r := bytes.NewReader(b)
-	return NewValueFromReader(r)
+	return NewValueFromReader(r, options...)
 }
 
 func objectFromValue(v *Value, err error) (*Object, error) {
@@ -137,12 +145,124 @@ func objectFromValue(v *Value, err error) (*Object, error) {
 	return o, nil
 }
 
-func NewObjectFromBytes(b []byte) (*Object, error) {
-	return objectFromValue(NewValueFromBytes(b))
+func NewObjectFromBytes(b []byte, options ...ParseOption) (*Object, error) {
+	return objectFromValue(NewValueFromBytes(b, options...))
 }
 
-func NewObjectFromReader(reader io.Reader) (*Object, error) {
-	return objectFromValue(NewValueFromReader(reader))
+func NewObjectFromReader(reader io.Reader, options ...ParseOption) (*Object, error) {
+	return objectFromValue(NewValueFromReader(reader, options...))
+}
+
+// Creates a new value from golang data.
+// Accepts the types jason uses to represent JSON (see Interface()) as well as
+// golang numbers, *Value, *Object, []*Value and map[string]*Value.
+// Other data is converted through encoding/json.
+// Example:
+//		v, err := NewValue(map[string]interface{}{"name": "anton", "age": 29})
+func NewValue(data interface{}) (*Value, error) {
+	normalized, err := normalize(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Value{normalized, true}, nil
+}
+
+// Converts golang data into the representation used by Value.
+func normalize(data interface{}) (interface{}, error) {
+	switch data := data.(type) {
+	case nil, bool, string, json.Number:
+		return data, nil
+	case int:
+		return json.Number(strconv.FormatInt(int64(data), 10)), nil
+	case int8:
+		return json.Number(strconv.FormatInt(int64(data), 10)), nil
+	case int16:
+		return json.Number(strconv.FormatInt(int64(data), 10)), nil
+	case int32:
+		return json.Number(strconv.FormatInt(int64(data), 10)), nil
+	case int64:
+		return json.Number(strconv.FormatInt(data, 10)), nil
+	case uint:
+		return json.Number(strconv.FormatUint(uint64(data), 10)), nil
+	case uint8:
+		return json.Number(strconv.FormatUint(uint64(data), 10)), nil
+	case uint16:
+		return json.Number(strconv.FormatUint(uint64(data), 10)), nil
+	case uint32:
+		return json.Number(strconv.FormatUint(uint64(data), 10)), nil
+	case uint64:
+		return json.Number(strconv.FormatUint(data, 10)), nil
+	case float32:
+		return normalizeFloat(float64(data), 32)
+	case float64:
+		return normalizeFloat(data, 64)
+	case *Value:
+		if data == nil {
+			return nil, nil
+		}
+		return data.data, nil
+	case *Object:
+		if data == nil {
+			return nil, nil
+		}
+		return data.data, nil
+	case []interface{}:
+		array := make([]interface{}, len(data))
+		for i, element := range data {
+			normalized, err := normalize(element)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = normalized
+		}
+		return array, nil
+	case []*Value:
+		array := make([]interface{}, len(data))
+		for i, element := range data {
+			array[i], _ = normalize(element)
+		}
+		return array, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(data))
+		for key, element := range data {
+			normalized, err := normalize(element)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = normalized
+		}
+		return m, nil
+	case map[string]*Value:
+		m := make(map[string]interface{}, len(data))
+		for key, element := range data {
+			m[key], _ = normalize(element)
+		}
+		return m, nil
+	}
+
+	b, err := json.Marshal(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := NewValueFromBytes(b)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.data, nil
+}
+
+func normalizeFloat(f float64, bitSize int) (interface{}, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("jason: unsupported number %v", f)
+	}
+
+	return json.Number(strconv.FormatFloat(f, 'g', -1, bitSize)), nil
 }
 
 // Marshal into bytes.
@@ -155,6 +275,66 @@ func (v *Value) MarshalJSON() ([]byte, error) {
 	return v.Marshal()
 }
 
+// Unmarshal from bytes, with numbers kept as json.Number like NewValueFromReader.
+// Lets values be used as fields of structs decoded with encoding/json.
+// A json null is kept as an existing null value.
+// Example:
+//		var envelope struct {
+//			Type    string
+//			Payload *jason.Value
+//		}
+//		err := json.Unmarshal(b, &envelope)
+func (v *Value) UnmarshalJSON(b []byte) error {
+	parsed, err := NewValueFromBytes(b)
+
+	if err != nil {
+		return err
+	}
+
+	*v = Value{parsed.data, true}
+
+	return nil
+}
+
+// Unmarshal from text holding a json document, for configuration decoders
+// and flag.TextVar. Behaves like UnmarshalJSON.
+func (v *Value) UnmarshalText(text []byte) error {
+	return v.UnmarshalJSON(text)
+}
+
+// Unmarshal from bytes, with numbers kept as json.Number like NewObjectFromReader.
+// Lets objects be used as fields of structs decoded with encoding/json.
+// Returns ErrNotObject if the json is not an object. A json null leaves the object unchanged,
+// like encoding/json does for other types.
+// Example:
+//		var envelope struct {
+//			Type    string
+//			Payload *jason.Object
+//		}
+//		err := json.Unmarshal(b, &envelope)
+func (v *Object) UnmarshalJSON(b []byte) error {
+	if string(bytes.TrimSpace(b)) == "null" {
+		return nil
+	}
+
+	o, err := NewObjectFromBytes(b)
+
+	if err != nil {
+		return err
+	}
+
+	o.exists = true
+	*v = *o
+
+	return nil
+}
+
+// Unmarshal from text holding a json object, for configuration decoders
+// and flag.TextVar. Behaves like UnmarshalJSON.
+func (v *Object) UnmarshalText(text []byte) error {
+	return v.UnmarshalJSON(text)
+}
+
 // Get the interyling data as interface
 func (v *Value) Interface() interface{} {
 	return v.data
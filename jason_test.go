@@ -1,7 +1,9 @@
 package jason
 
 import (
+	"encoding/json"
 	"log"
+	"math"
 	"testing"
 )
 
@@ -293,3 +295,81 @@ func TestErrors(t *testing.T) {
 	}
 
 }
+
+func TestNewValue(t *testing.T) {
+	assert := NewAssert(t)
+
+	name, _ := NewValue("anton")
+
+	v, err := NewValue(map[string]interface{}{
+		"name":    name,
+		"age":     29,
+		"height":  1.85,
+		"list":    []interface{}{uint8(1), "two", nil},
+		"values":  []*Value{name},
+		"struct":  struct{ A int }{A: 1},
+		"nothing": nil,
+	})
+	assert.True(err == nil, "failed to create value")
+
+	o, err := v.Object()
+	assert.True(err == nil, "value should be an object")
+
+	s, err := o.GetString("name")
+	assert.True(s == "anton" && err == nil, "name mismatch")
+
+	n, err := o.GetInt64("age")
+	assert.True(n == 29 && err == nil, "age mismatch")
+
+	f, err := o.GetFloat64("height")
+	assert.True(f == 1.85 && err == nil, "height mismatch")
+
+	list, err := o.GetValueArray("list")
+	assert.True(len(list) == 3 && err == nil, "list mismatch")
+
+	a, err := o.GetInt64("struct", "A")
+	assert.True(a == 1 && err == nil, "struct should be converted through encoding/json")
+
+	err = o.GetNull("nothing")
+	assert.True(err == nil, "nothing should be null")
+
+	_, err = NewValue(math.NaN())
+	assert.True(err != nil, "NaN should not be accepted")
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	assert := NewAssert(t)
+
+	var envelope struct {
+		Type    string
+		Payload *Object
+		Extra   Value
+		Missing *Value
+		Nothing *Object
+	}
+
+	err := json.Unmarshal([]byte(`{"Type": "user", "Payload": {"id": 12345678901234567890, "name": "anton"}, "Extra": null, "Missing": null}`), &envelope)
+	assert.True(err == nil, "failed to unmarshal envelope")
+	assert.True(envelope.Type == "user", "typed fields should be decoded")
+
+	id, err := envelope.Payload.GetNumber("id")
+	assert.True(err == nil && id == "12345678901234567890", "numbers should be kept as json.Number")
+
+	name, err := envelope.Payload.GetString("name")
+	assert.True(err == nil && name == "anton", "objects should be decoded")
+
+	assert.True(envelope.Extra.Null() == nil, "json null should be decoded as an existing null")
+	assert.True(envelope.Missing == nil && envelope.Nothing == nil, "null pointers should stay nil")
+
+	b, err := json.Marshal(&envelope)
+	assert.True(err == nil && string(b) == `{"Type":"user","Payload":{"id":12345678901234567890,"name":"anton"},"Extra":null,"Missing":null,"Nothing":null}`, "envelopes should marshal again, got "+string(b))
+
+	var o Object
+	err = json.Unmarshal([]byte(`[1, 2]`), &o)
+	assert.True(err == ErrNotObject, "arrays should not unmarshal into objects")
+
+	var v Value
+	err = v.UnmarshalText([]byte(`[1, 2]`))
+	array, _ := v.Array()
+	assert.True(err == nil && len(array) == 2, "text should be parsed as json")
+}
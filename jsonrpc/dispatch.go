@@ -0,0 +1,214 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/antonholmquist/jason"
+)
+
+// Handler answers a request. The result is converted with jason.NewValue.
+// Returning an *Error sends it to the client as is. Other errors, and panics, are sent as
+// internal errors with the standard message only, so that their details stay on the server.
+type Handler func(ctx context.Context, params *jason.Value) (interface{}, error)
+
+// Dispatcher calls the handler registered for the method of each request and builds the responses.
+// It doesn't depend on a transport: Handle works on single messages, and Serve on streams
+// of json messages like pipes, sockets or standard input and output.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	hook     func(ctx context.Context, method string, err error)
+}
+
+// Creates a dispatcher without methods.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string]Handler)}
+}
+
+// Registers the handler for a method, replacing any previous one.
+// Example:
+//		d.Register("subtract", func(ctx context.Context, params *jason.Value) (interface{}, error) {
+//			...
+//		})
+func (d *Dispatcher) Register(method string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[method] = handler
+}
+
+// Sets a function that is called with the details of every internal error: errors other than
+// *Error returned by handlers, recovered panics and results that can't be converted.
+// Also called for notifications, which get no response. Returns the dispatcher.
+// Example:
+//		d := jsonrpc.NewDispatcher().OnError(func(ctx context.Context, method string, err error) {
+//			log.Printf("%s failed: %v", method, err)
+//		})
+func (d *Dispatcher) OnError(hook func(ctx context.Context, method string, err error)) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hook = hook
+	return d
+}
+
+// Answers a message holding a request, a notification or a batch.
+// Returns the response message, or nil if there is nothing to answer because
+// the message only held notifications.
+func (d *Dispatcher) Handle(ctx context.Context, message []byte) []byte {
+	values, batch, err := Parse(message)
+
+	if err != nil {
+		var rpcErr *Error
+		errors.As(err, &rpcErr)
+		return mustMarshal(NewErrorResponse(nil, rpcErr))
+	}
+
+	var responses []*Response
+
+	for _, v := range values {
+		if res := d.call(ctx, v); res != nil {
+			responses = append(responses, res)
+		}
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+
+	if !batch {
+		return mustMarshal(responses[0])
+	}
+
+	return mustMarshal(responses)
+}
+
+// Reads json messages from r until it ends and writes each response to w, followed by a newline.
+// Returns nil when r ends, or the error that stopped serving. A message that is not json
+// is answered with a parse error, after which serving stops since the stream can't be resynchronized.
+// Example:
+//		err := d.Serve(ctx, os.Stdin, os.Stdout)
+func (d *Dispatcher) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var message json.RawMessage
+		err := dec.Decode(&message)
+
+		if err == io.EOF {
+			return nil
+		}
+
+		var response []byte
+
+		if err != nil {
+			response = mustMarshal(NewErrorResponse(nil, NewError(CodeParseError, "", nil)))
+		} else {
+			response = d.Handle(ctx, message)
+		}
+
+		if response != nil {
+			if _, writeErr := w.Write(append(response, '\n')); writeErr != nil {
+				return writeErr
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Answers a single request, returning nil for notifications.
+func (d *Dispatcher) call(ctx context.Context, v *jason.Value) *Response {
+	req, err := ParseRequest(v)
+
+	if err != nil {
+		var rpcErr *Error
+		errors.As(err, &rpcErr)
+		return NewErrorResponse(req.ID, rpcErr)
+	}
+
+	d.mu.RLock()
+	handler, ok := d.handlers[req.Method]
+	d.mu.RUnlock()
+
+	var result interface{}
+
+	if !ok {
+		err = NewError(CodeMethodNotFound, "", nil)
+	} else {
+		result, err = invoke(ctx, handler, req.Params)
+	}
+
+	if err != nil {
+		var rpcErr *Error
+
+		if !errors.As(err, &rpcErr) {
+			rpcErr = d.internalError(ctx, req.Method, err)
+		}
+
+		if req.IsNotification() {
+			return nil
+		}
+
+		return NewErrorResponse(req.ID, rpcErr)
+	}
+
+	if req.IsNotification() {
+		return nil
+	}
+
+	res, err := NewResult(req.ID, result)
+
+	if err != nil {
+		return NewErrorResponse(req.ID, d.internalError(ctx, req.Method, err))
+	}
+
+	return res
+}
+
+// Reports err to the hook and returns the internal error sent in its place.
+func (d *Dispatcher) internalError(ctx context.Context, method string, err error) *Error {
+	d.mu.RLock()
+	hook := d.hook
+	d.mu.RUnlock()
+
+	if hook != nil {
+		hook(ctx, method, err)
+	}
+
+	return NewError(CodeInternalError, "", nil)
+}
+
+// Calls the handler, turning panics into errors.
+func invoke(ctx context.Context, handler Handler, params *jason.Value) (result interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("jsonrpc: handler panicked: %v", p)
+		}
+	}()
+
+	return handler(ctx, params)
+}
+
+// Marshals a response, which can't fail since all its values are jason values.
+func mustMarshal(v interface{}) []byte {
+	b, err := json.Marshal(v)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
@@ -0,0 +1,168 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/antonholmquist/jason"
+)
+
+func newDispatcher() *Dispatcher {
+	d := NewDispatcher()
+
+	d.Register("subtract", func(ctx context.Context, params *jason.Value) (interface{}, error) {
+		array, err := params.Array()
+
+		if err != nil || len(array) != 2 {
+			return nil, NewError(CodeInvalidParams, "", nil)
+		}
+
+		a, errA := array[0].Int64()
+		b, errB := array[1].Int64()
+
+		if errA != nil || errB != nil {
+			return nil, NewError(CodeInvalidParams, "", nil)
+		}
+
+		return a - b, nil
+	})
+
+	d.Register("fail", func(ctx context.Context, params *jason.Value) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+
+	d.Register("panic", func(ctx context.Context, params *jason.Value) (interface{}, error) {
+		panic("oops")
+	})
+
+	d.Register("notify", func(ctx context.Context, params *jason.Value) (interface{}, error) {
+		return nil, nil
+	})
+
+	return d
+}
+
+func TestHandle(t *testing.T) {
+	d := newDispatcher()
+	ctx := context.Background()
+
+	cases := []struct {
+		message  string
+		expected string
+	}{
+		{`{"jsonrpc": "2.0", "method": "subtract", "params": [42, 23], "id": 1}`, `{"id":1,"jsonrpc":"2.0","result":19}`},
+		{`{"jsonrpc": "2.0", "method": "notify", "params": [1]}`, ``},
+		{`{"jsonrpc": "2.0", "method": "foobar", "id": "1"}`, `{"error":{"code":-32601,"message":"Method not found"},"id":"1","jsonrpc":"2.0"}`},
+		{`{"jsonrpc": "2.0", "method": "foobar, "params": "bar", "baz]`, `{"error":{"code":-32700,"message":"Parse error"},"id":null,"jsonrpc":"2.0"}`},
+		{`{"jsonrpc": "2.0", "method": 1, "params": "bar"}`, `{"error":{"code":-32600,"data":"method must be a string","message":"Invalid Request"},"id":null,"jsonrpc":"2.0"}`},
+		{`[]`, `{"error":{"code":-32600,"data":"empty batch","message":"Invalid Request"},"id":null,"jsonrpc":"2.0"}`},
+		{`[1]`, `[{"error":{"code":-32600,"data":"request must be an object","message":"Invalid Request"},"id":null,"jsonrpc":"2.0"}]`},
+		{`{"jsonrpc": "2.0", "method": "subtract", "params": [1], "id": 2}`, `{"error":{"code":-32602,"message":"Invalid params"},"id":2,"jsonrpc":"2.0"}`},
+		{`{"jsonrpc": "2.0", "method": "fail", "id": 3}`, `{"error":{"code":-32603,"message":"Internal error"},"id":3,"jsonrpc":"2.0"}`},
+		{`{"jsonrpc": "2.0", "method": "panic", "id": 4}`, `{"error":{"code":-32603,"message":"Internal error"},"id":4,"jsonrpc":"2.0"}`},
+		{`[{"jsonrpc": "2.0", "method": "notify"}, {"jsonrpc": "2.0", "method": "notify"}]`, ``},
+		{
+			`[{"jsonrpc": "2.0", "method": "subtract", "params": [5, 3], "id": "a"}, {"jsonrpc": "2.0", "method": "notify"}, {"foo": "boo"}]`,
+			`[{"id":"a","jsonrpc":"2.0","result":2},{"error":{"code":-32600,"data":"jsonrpc must be \"2.0\"","message":"Invalid Request"},"id":null,"jsonrpc":"2.0"}]`,
+		},
+	}
+
+	for _, c := range cases {
+		response := string(d.Handle(ctx, []byte(c.message)))
+
+		if response != c.expected {
+			t.Errorf("%s:\ngot      %s\nexpected %s", c.message, response, c.expected)
+		}
+	}
+}
+
+func TestOnError(t *testing.T) {
+	var reported []string
+
+	d := newDispatcher().OnError(func(ctx context.Context, method string, err error) {
+		reported = append(reported, method+": "+err.Error())
+	})
+
+	d.Register("unencodable", func(ctx context.Context, params *jason.Value) (interface{}, error) {
+		return make(chan int), nil
+	})
+
+	ctx := context.Background()
+
+	for _, message := range []string{
+		`{"jsonrpc": "2.0", "method": "fail", "id": 1}`,
+		`{"jsonrpc": "2.0", "method": "panic", "id": 2}`,
+		`{"jsonrpc": "2.0", "method": "fail"}`,
+		`{"jsonrpc": "2.0", "method": "unencodable", "id": 3}`,
+		`{"jsonrpc": "2.0", "method": "subtract", "params": [1], "id": 4}`,
+	} {
+		if response := string(d.Handle(ctx, []byte(message))); strings.Contains(response, "boom") || strings.Contains(response, "oops") {
+			t.Errorf("%s: internal details should not be sent, got %s", message, response)
+		}
+	}
+
+	if len(reported) != 4 {
+		t.Fatalf("expected 4 reported errors, got %q", reported)
+	}
+
+	if reported[0] != "fail: boom" || reported[1] != "panic: jsonrpc: handler panicked: oops" || reported[2] != "fail: boom" || !strings.HasPrefix(reported[3], "unencodable: ") {
+		t.Errorf("unexpected reported errors %q", reported)
+	}
+}
+
+func TestServe(t *testing.T) {
+	d := newDispatcher()
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+
+	done := make(chan error)
+	go func() {
+		done <- d.Serve(context.Background(), serverReader, serverWriter)
+		serverWriter.Close()
+	}()
+
+	responses := bufio.NewScanner(clientReader)
+
+	exchange := func(message string) string {
+		io.WriteString(clientWriter, message+"\n")
+
+		if !responses.Scan() {
+			t.Fatalf("no response to %s", message)
+		}
+
+		return responses.Text()
+	}
+
+	if r := exchange(`{"jsonrpc": "2.0", "method": "subtract", "params": [42, 23], "id": 1}`); r != `{"id":1,"jsonrpc":"2.0","result":19}` {
+		t.Errorf("unexpected response %s", r)
+	}
+
+	// Notifications get no response, so the next line answers the following request.
+	io.WriteString(clientWriter, `{"jsonrpc": "2.0", "method": "notify"}`)
+
+	if r := exchange(`{"jsonrpc": "2.0", "method": "subtract", "params": [2, 1], "id": 2}`); r != `{"id":2,"jsonrpc":"2.0","result":1}` {
+		t.Errorf("unexpected response %s", r)
+	}
+
+	if r := exchange(`{"jsonrpc": "2.0", "method" }`); !strings.Contains(r, `"code":-32700`) {
+		t.Errorf("expected a parse error, got %s", r)
+	}
+
+	if err := <-done; err == nil {
+		t.Errorf("serving should stop after a parse error")
+	}
+
+	clientWriter.Close()
+
+	d2 := newDispatcher()
+	var out strings.Builder
+	err := d2.Serve(context.Background(), strings.NewReader(`{"jsonrpc": "2.0", "method": "subtract", "params": [1, 1], "id": 1}`), &out)
+	if err != nil || out.String() != `{"id":1,"jsonrpc":"2.0","result":0}`+"\n" {
+		t.Errorf("expected one response and a clean end, got %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,390 @@
+// Package jsonrpc parses and builds JSON-RPC 2.0 messages, with params and results as jason values.
+//
+// Parse splits a message into its requests or responses, which are then
+// validated against the specification by ParseRequest and ParseResponse:
+//		values, batch, err := jsonrpc.Parse(b)
+//		for _, v := range values {
+//			req, err := jsonrpc.ParseRequest(v)
+//			...
+//		}
+//
+// A Dispatcher calls registered handlers for incoming requests and builds the
+// responses, independent of the transport:
+//		d := jsonrpc.NewDispatcher()
+//		d.Register("add", func(ctx context.Context, params *jason.Value) (interface{}, error) {
+//			...
+//		})
+//		err := d.Serve(ctx, conn, conn)
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/antonholmquist/jason"
+)
+
+// The protocol version written in every message.
+const Version = "2.0"
+
+// Error codes defined by the specification.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+var messages = map[int]string{
+	CodeParseError:     "Parse error",
+	CodeInvalidRequest: "Invalid Request",
+	CodeMethodNotFound: "Method not found",
+	CodeInvalidParams:  "Invalid params",
+	CodeInternalError:  "Internal error",
+}
+
+// Error is a JSON-RPC error object. It implements error, so handlers can return it.
+type Error struct {
+	Code    int
+	Message string
+	Data    *jason.Value // Additional information, nil if absent
+}
+
+// Creates an error object. An empty message is replaced with the standard message for the code.
+// Example:
+//		return nil, jsonrpc.NewError(jsonrpc.CodeInvalidParams, "expected two numbers", nil)
+func NewError(code int, message string, data *jason.Value) *Error {
+	if message == "" {
+		message = messages[code]
+	}
+
+	return &Error{Code: code, Message: message, Data: data}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc: %s (%d)", e.Message, e.Code)
+}
+
+func (e *Error) value() map[string]interface{} {
+	m := map[string]interface{}{
+		"code":    e.Code,
+		"message": e.Message,
+	}
+
+	if e.Data != nil {
+		m["data"] = e.Data
+	}
+
+	return m
+}
+
+// MarshalJSON writes the error object.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return marshal(e.value())
+}
+
+// Request is a request or, if it has no ID, a notification.
+type Request struct {
+	Method string
+	Params *jason.Value // An array or object, nil if absent
+	ID     *jason.Value // A string, number or null, nil for notifications
+}
+
+// Reports whether the request is a notification, which gets no response.
+func (r *Request) IsNotification() bool {
+	return r.ID == nil
+}
+
+// MarshalJSON writes the request object.
+func (r *Request) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{
+		"jsonrpc": Version,
+		"method":  r.Method,
+	}
+
+	if r.Params != nil {
+		m["params"] = r.Params
+	}
+
+	if r.ID != nil {
+		m["id"] = r.ID
+	}
+
+	return marshal(m)
+}
+
+// Response is the result of a request, or the error it failed with.
+type Response struct {
+	ID     *jason.Value // The ID of the request, null if it couldn't be read
+	Result *jason.Value // The result, nil if Error is set
+	Error  *Error
+}
+
+// MarshalJSON writes the response object.
+func (r *Response) MarshalJSON() ([]byte, error) {
+	id := r.ID
+	if id == nil {
+		id = null()
+	}
+
+	m := map[string]interface{}{
+		"jsonrpc": Version,
+		"id":      id,
+	}
+
+	if r.Error != nil {
+		m["error"] = r.Error.value()
+	} else if r.Result != nil {
+		m["result"] = r.Result
+	} else {
+		m["result"] = null()
+	}
+
+	return marshal(m)
+}
+
+// Creates a request. The id must be a string or a number, and params an array, a map, a struct or nil.
+// Params and id are converted with jason.NewValue. Null ids are only accepted from peers by ParseRequest,
+// use NewNotification for requests without a response.
+// Example:
+//		req, err := jsonrpc.NewRequest(1, "subtract", []int{42, 23})
+func NewRequest(id interface{}, method string, params interface{}) (*Request, error) {
+	r, err := NewNotification(method, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.ID, err = jason.NewValue(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isValidID(r.ID) || r.ID.Null() == nil {
+		return nil, fmt.Errorf("jsonrpc: id must be a string or a number, not %T", id)
+	}
+
+	return r, nil
+}
+
+// Creates a notification, a request without ID. Params must be an array, a map, a struct or nil.
+// Example:
+//		n, err := jsonrpc.NewNotification("update", map[string]interface{}{"progress": 50})
+func NewNotification(method string, params interface{}) (*Request, error) {
+	r := &Request{Method: method}
+
+	if params == nil {
+		return r, nil
+	}
+
+	p, err := jason.NewValue(params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isStructured(p) {
+		return nil, fmt.Errorf("jsonrpc: params must be an array or an object, not %T", params)
+	}
+
+	r.Params = p
+
+	return r, nil
+}
+
+// Creates a successful response to a request. The result is converted with jason.NewValue.
+// Example:
+//		res, err := jsonrpc.NewResult(req.ID, 19)
+func NewResult(id *jason.Value, result interface{}) (*Response, error) {
+	v, err := jason.NewValue(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{ID: id, Result: v}, nil
+}
+
+// Creates a failed response to a request.
+// Example:
+//		res := jsonrpc.NewErrorResponse(req.ID, jsonrpc.NewError(jsonrpc.CodeMethodNotFound, "", nil))
+func NewErrorResponse(id *jason.Value, err *Error) *Response {
+	return &Response{ID: id, Error: err}
+}
+
+// Splits a message into the values of its requests or responses.
+// A batch is a json array of them, and batch reports whether the message was one.
+// Returns a parse error if the message is not json, and an invalid request error for an empty batch.
+// The error is always an *Error.
+func Parse(b []byte) (values []*jason.Value, batch bool, err error) {
+	if err := json.Unmarshal(b, new(json.RawMessage)); err != nil {
+		return nil, false, NewError(CodeParseError, "", nil)
+	}
+
+	v, err := jason.NewValueFromBytes(b)
+
+	if err != nil {
+		return nil, false, NewError(CodeParseError, "", nil)
+	}
+
+	array, err := v.Array()
+
+	if err != nil {
+		return []*jason.Value{v}, false, nil
+	}
+
+	if len(array) == 0 {
+		return nil, true, invalid("empty batch")
+	}
+
+	return array, true, nil
+}
+
+// Parses and validates a request or notification.
+// Returns an *Error with CodeInvalidRequest if v doesn't conform to the specification.
+// The returned request holds the ID if it could be read, so that the error can still be answered.
+func ParseRequest(v *jason.Value) (*Request, error) {
+	o, err := v.Object()
+
+	if err != nil {
+		return &Request{ID: null()}, invalid("request must be an object")
+	}
+
+	r := &Request{ID: null()}
+
+	if id, err := o.GetValue("id"); err == nil {
+		if !isValidID(id) {
+			return r, invalid("id must be a string, a number or null")
+		}
+
+		r.ID = id
+	} else {
+		r.ID = nil
+	}
+
+	if version, err := o.GetString("jsonrpc"); err != nil || version != Version {
+		return r, invalid(`jsonrpc must be "2.0"`)
+	}
+
+	if r.Method, err = o.GetString("method"); err != nil {
+		return r, invalid("method must be a string")
+	}
+
+	if params, err := o.GetValue("params"); err == nil {
+		if !isStructured(params) {
+			return r, invalid("params must be an array or an object")
+		}
+
+		r.Params = params
+	}
+
+	return r, nil
+}
+
+// Parses and validates a response.
+// Returns an *Error with CodeInvalidRequest if v doesn't conform to the specification.
+func ParseResponse(v *jason.Value) (*Response, error) {
+	o, err := v.Object()
+
+	if err != nil {
+		return nil, invalid("response must be an object")
+	}
+
+	if version, err := o.GetString("jsonrpc"); err != nil || version != Version {
+		return nil, invalid(`jsonrpc must be "2.0"`)
+	}
+
+	r := &Response{}
+
+	if r.ID, err = o.GetValue("id"); err != nil || !isValidID(r.ID) {
+		return nil, invalid("id must be a string, a number or null")
+	}
+
+	result, resultErr := o.GetValue("result")
+	errorValue, errorErr := o.GetValue("error")
+
+	switch {
+	case resultErr == nil && errorErr == nil:
+		return nil, invalid("response must not have both result and error")
+	case resultErr == nil:
+		r.Result = result
+	case errorErr == nil:
+		if r.Error, err = parseError(errorValue); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, invalid("response must have result or error")
+	}
+
+	return r, nil
+}
+
+// Parses and validates an error object.
+func parseError(v *jason.Value) (*Error, error) {
+	o, err := v.Object()
+
+	if err != nil {
+		return nil, invalid("error must be an object")
+	}
+
+	code, err := o.GetInteger(jason.StrictIntegers, 0, "code")
+
+	if err != nil {
+		return nil, invalid("error code must be an integer")
+	}
+
+	message, err := o.GetString("message")
+
+	if err != nil {
+		return nil, invalid("error message must be a string")
+	}
+
+	e := &Error{Code: int(code), Message: message}
+
+	if data, err := o.GetValue("data"); err == nil {
+		e.Data = data
+	}
+
+	return e, nil
+}
+
+func invalid(reason string) *Error {
+	data, _ := jason.NewValue(reason)
+	return NewError(CodeInvalidRequest, "", data)
+}
+
+func isValidID(v *jason.Value) bool {
+	switch v.Interface().(type) {
+	case string, json.Number, nil:
+		return true
+	}
+
+	return false
+}
+
+func isStructured(v *jason.Value) bool {
+	switch v.Interface().(type) {
+	case []interface{}, map[string]interface{}:
+		return true
+	}
+
+	return false
+}
+
+func null() *jason.Value {
+	v, _ := jason.NewValue(nil)
+	return v
+}
+
+// Marshals a message with sorted keys, like jason.
+func marshal(m map[string]interface{}) ([]byte, error) {
+	v, err := jason.NewValue(m)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Marshal()
+}
@@ -0,0 +1,154 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/antonholmquist/jason"
+)
+
+func mustValue(t *testing.T, s string) *jason.Value {
+	t.Helper()
+
+	v, err := jason.NewValueFromBytes([]byte(s))
+	if err != nil {
+		t.Fatalf("invalid json %s: %v", s, err)
+	}
+
+	return v
+}
+
+func TestParseRequest(t *testing.T) {
+	req, err := ParseRequest(mustValue(t, `{"jsonrpc": "2.0", "method": "subtract", "params": {"minuend": 42, "subtrahend": 23}, "id": 3}`))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if req.Method != "subtract" || req.IsNotification() {
+		t.Errorf("unexpected request %+v", req)
+	}
+
+	if id, _ := req.ID.Int64(); id != 3 {
+		t.Errorf("expected id 3, got %v", req.ID.Interface())
+	}
+
+	params, _ := req.Params.Object()
+	if n, _ := params.GetInt64("minuend"); n != 42 {
+		t.Errorf("expected params to be kept, got %v", req.Params.Interface())
+	}
+
+	req, err = ParseRequest(mustValue(t, `{"jsonrpc": "2.0", "method": "update", "params": [1, 2]}`))
+	if err != nil || !req.IsNotification() {
+		t.Errorf("expected a notification, got %+v, %v", req, err)
+	}
+
+	invalid := []string{
+		`1`,
+		`{"jsonrpc": "1.0", "method": "x", "id": 1}`,
+		`{"method": "x", "id": 1}`,
+		`{"jsonrpc": "2.0", "method": 1, "id": 1}`,
+		`{"jsonrpc": "2.0", "method": "x", "params": "bar", "id": 1}`,
+		`{"jsonrpc": "2.0", "method": "x", "id": {}}`,
+	}
+
+	for _, s := range invalid {
+		_, err := ParseRequest(mustValue(t, s))
+
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidRequest {
+			t.Errorf("%s: expected invalid request, got %v", s, err)
+		}
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	res, err := ParseResponse(mustValue(t, `{"jsonrpc": "2.0", "result": 19, "id": 1}`))
+	if n, _ := res.Result.Int64(); err != nil || n != 19 || res.Error != nil {
+		t.Errorf("unexpected response %+v, %v", res, err)
+	}
+
+	res, err = ParseResponse(mustValue(t, `{"jsonrpc": "2.0", "error": {"code": -32601, "message": "Method not found", "data": "x"}, "id": "1"}`))
+	if err != nil || res.Error == nil || res.Error.Code != CodeMethodNotFound || res.Error.Data == nil {
+		t.Errorf("unexpected error response %+v, %v", res, err)
+	}
+
+	invalid := []string{
+		`{"jsonrpc": "2.0", "result": 19}`,
+		`{"jsonrpc": "2.0", "id": 1}`,
+		`{"jsonrpc": "2.0", "result": 19, "error": {"code": 1, "message": "x"}, "id": 1}`,
+		`{"jsonrpc": "2.0", "error": {"code": 1.5, "message": "x"}, "id": 1}`,
+		`{"jsonrpc": "2.0", "error": {"code": 1}, "id": 1}`,
+	}
+
+	for _, s := range invalid {
+		if _, err := ParseResponse(mustValue(t, s)); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	values, batch, err := Parse([]byte(`[{"jsonrpc": "2.0", "method": "a"}, 1]`))
+	if err != nil || !batch || len(values) != 2 {
+		t.Errorf("expected a batch of two, got %d, %v, %v", len(values), batch, err)
+	}
+
+	values, batch, err = Parse([]byte(`{"jsonrpc": "2.0", "method": "a"}`))
+	if err != nil || batch || len(values) != 1 {
+		t.Errorf("expected a single message, got %d, %v, %v", len(values), batch, err)
+	}
+
+	var rpcErr *Error
+
+	_, _, err = Parse([]byte(`{"jsonrpc": "2.0", "method"`))
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeParseError {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+
+	_, _, err = Parse([]byte(`[]`))
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidRequest {
+		t.Errorf("expected an invalid request for an empty batch, got %v", err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	req, err := NewRequest(1, "subtract", []int{42, 23})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	notification, _ := NewNotification("update", nil)
+	result, _ := NewResult(req.ID, 19)
+	failure := NewErrorResponse(nil, NewError(CodeInvalidRequest, "", nil))
+
+	cases := []struct {
+		message  interface{}
+		expected string
+	}{
+		{req, `{"id":1,"jsonrpc":"2.0","method":"subtract","params":[42,23]}`},
+		{notification, `{"jsonrpc":"2.0","method":"update"}`},
+		{result, `{"id":1,"jsonrpc":"2.0","result":19}`},
+		{failure, `{"error":{"code":-32600,"message":"Invalid Request"},"id":null,"jsonrpc":"2.0"}`},
+		{[]*Request{req, notification}, `[{"id":1,"jsonrpc":"2.0","method":"subtract","params":[42,23]},{"jsonrpc":"2.0","method":"update"}]`},
+	}
+
+	for _, c := range cases {
+		b, err := json.Marshal(c.message)
+		if err != nil || string(b) != c.expected {
+			t.Errorf("got %s, %v, expected %s", b, err, c.expected)
+		}
+	}
+
+	if _, err := NewRequest(true, "x", nil); err == nil {
+		t.Errorf("boolean ids should be rejected")
+	}
+
+	if _, err := NewRequest(nil, "x", nil); err == nil {
+		t.Errorf("nil ids should be rejected")
+	}
+
+	if _, err := NewNotification("x", 42); err == nil {
+		t.Errorf("scalar params should be rejected")
+	}
+}
@@ -0,0 +1,372 @@
+package jason
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LogOptions control how values are written to structured logs.
+type LogOptions struct {
+	MaxDepth       int              // Objects and arrays nested deeper are replaced with "{...}" or "[...]", unlimited if 0
+	MaxArrayLength int              // Longer arrays are cut and end with a note of how many elements were left out, unlimited if 0
+	Redaction      *RedactionPolicy // Values to redact before logging, if any
+}
+
+// Returns the value for log/slog, so that loggers write objects as nested groups
+// instead of a single string. *Object implements slog.LogValuer through this method too.
+// Arrays are logged as a single attribute value, since slog has no array kind.
+// Example:
+//		slog.Info("request received", "body", body)
+func (v *Value) LogValue() slog.Value {
+	return logValue(v.data, LogOptions{}, 1)
+}
+
+// Returns a slog.LogValuer that logs the value with the given options.
+// The options are only applied if the log record is actually written.
+// Example:
+//		slog.Info("request received", "body", body.LogValuer(jason.LogOptions{MaxDepth: 3, Redaction: &policy}))
+func (v *Value) LogValuer(opts LogOptions) slog.LogValuer {
+	return logValuer{v, opts}
+}
+
+type logValuer struct {
+	v    *Value
+	opts LogOptions
+}
+
+func (l logValuer) LogValue() slog.Value {
+	v := l.v
+
+	if l.opts.Redaction != nil {
+		redacted, err := v.Redact(*l.opts.Redaction)
+
+		if err != nil {
+			return slog.StringValue(DefaultMask)
+		}
+
+		v = redacted
+	}
+
+	return logValue(v.data, l.opts, 1)
+}
+
+// Converts data at the given depth into a slog value.
+func logValue(data interface{}, opts LogOptions, depth int) slog.Value {
+	switch d := data.(type) {
+	case string:
+		return slog.StringValue(d)
+	case bool:
+		return slog.BoolValue(d)
+	case json.Number:
+		if i, err := d.Int64(); err == nil {
+			return slog.Int64Value(i)
+		}
+
+		if f, err := d.Float64(); err == nil {
+			return slog.Float64Value(f)
+		}
+
+		return slog.StringValue(string(d))
+	case map[string]interface{}:
+		if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+			return slog.StringValue("{...}")
+		}
+
+		attrs := make([]slog.Attr, 0, len(d))
+		for _, key := range slices.Sorted(maps.Keys(d)) {
+			attrs = append(attrs, slog.Attr{Key: key, Value: logValue(d[key], opts, depth+1)})
+		}
+
+		return slog.GroupValue(attrs...)
+	case []interface{}:
+		return slog.AnyValue(logArray(d, opts, depth))
+	}
+
+	return slog.AnyValue(nil)
+}
+
+// Applies the depth and length limits to array data, which is logged as is.
+func logArray(data interface{}, opts LogOptions, depth int) interface{} {
+	switch d := data.(type) {
+	case map[string]interface{}:
+		if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+			return "{...}"
+		}
+
+		m := make(map[string]interface{}, len(d))
+		for key, child := range d {
+			m[key] = logArray(child, opts, depth+1)
+		}
+
+		return m
+	case []interface{}:
+		if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+			return "[...]"
+		}
+
+		n := len(d)
+		if opts.MaxArrayLength > 0 && n > opts.MaxArrayLength {
+			n = opts.MaxArrayLength
+		}
+
+		array := make([]interface{}, 0, n+1)
+		for _, element := range d[:n] {
+			array = append(array, logArray(element, opts, depth+1))
+		}
+
+		if n < len(d) {
+			array = append(array, "... "+strconv.Itoa(len(d)-n)+" more")
+		}
+
+		return array
+	}
+
+	return data
+}
+
+// LogHandlerOptions configure a LogHandler.
+type LogHandlerOptions struct {
+	Level     slog.Leveler     // Minimum level to log, slog.LevelInfo if nil
+	AddSource bool             // Whether to add the source file and line of the log call
+	Redaction *RedactionPolicy // Attributes to redact from every record, with paths relative to the record
+}
+
+// LogHandler is a slog.Handler that writes each record as a line of JSON using Encoder.
+// The time, level, message and source come first, followed by the attributes in sorted order.
+type LogHandler struct {
+	opts   LogHandlerOptions
+	w      io.Writer
+	mu     *sync.Mutex
+	groups []groupOrAttrs
+}
+
+// An element of the handler state: either a group opened with WithGroup or attributes added with WithAttrs.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// Creates a new handler that writes to w. Options may be nil.
+// Example:
+//		logger := slog.New(jason.NewLogHandler(os.Stderr, nil))
+func NewLogHandler(w io.Writer, opts *LogHandlerOptions) *LogHandler {
+	h := &LogHandler{w: w, mu: &sync.Mutex{}}
+
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+
+	return h
+}
+
+// Reports whether records of the level are written.
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Returns a handler that adds the attributes to every record.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+// Returns a handler that puts the attributes of every record in a group.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *LogHandler) with(goa groupOrAttrs) *LogHandler {
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), goa)
+	return &h2
+}
+
+// Writes the record as a line of JSON.
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	root := make(group)
+	current := root
+
+	for _, goa := range h.groups {
+		if goa.group != "" {
+			child := make(group)
+			current[goa.group] = child
+			current = child
+		}
+
+		addAttrs(current, goa.attrs)
+	}
+
+	r.Attrs(func(attr slog.Attr) bool {
+		addAttrs(current, []slog.Attr{attr})
+		return true
+	})
+
+	attrs := &Value{root.data(), true}
+
+	if h.opts.Redaction != nil {
+		redacted, err := attrs.Redact(*h.opts.Redaction)
+
+		if err != nil {
+			redacted = &Value{map[string]interface{}{}, true}
+		}
+
+		attrs = redacted
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	buf.WriteString("{")
+
+	field := func(key string, v *Value) error {
+		if buf.Len() > 1 {
+			buf.WriteString(",")
+		}
+
+		if err := enc.Encode(&Value{key, true}); err != nil {
+			return err
+		}
+
+		buf.WriteString(":")
+
+		return enc.Encode(v)
+	}
+
+	if !r.Time.IsZero() {
+		field(slog.TimeKey, &Value{r.Time.Format(time.RFC3339Nano), true})
+	}
+
+	field(slog.LevelKey, &Value{r.Level.String(), true})
+
+	if h.opts.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		field(slog.SourceKey, &Value{map[string]interface{}{
+			"function": frame.Function,
+			"file":     frame.File,
+			"line":     json.Number(strconv.Itoa(frame.Line)),
+		}, true})
+	}
+
+	field(slog.MessageKey, &Value{r.Message, true})
+
+	data, _ := attrs.data.(map[string]interface{})
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		if err := field(key, &Value{data[key], true}); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.w.Write(buf.Bytes())
+
+	return err
+}
+
+// group collects the attributes of a log group, which become a json object unless empty.
+type group map[string]interface{}
+
+// Adds attributes to the group, resolving LogValuers and inlining groups with empty keys.
+func addAttrs(g group, attrs []slog.Attr) {
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+
+		if attr.Key == "" && value.Kind() != slog.KindGroup {
+			continue
+		}
+
+		if value.Kind() != slog.KindGroup {
+			g[attr.Key] = attrData(value)
+			continue
+		}
+
+		child := g
+
+		if attr.Key != "" {
+			child = make(group)
+			g[attr.Key] = child
+		}
+
+		addAttrs(child, value.Group())
+	}
+}
+
+// Returns the group as json data, leaving out groups without attributes like slog.JSONHandler does.
+func (g group) data() map[string]interface{} {
+	object := make(map[string]interface{}, len(g))
+
+	for key, child := range g {
+		if child, ok := child.(group); ok {
+			if data := child.data(); len(data) > 0 {
+				object[key] = data
+			}
+
+			continue
+		}
+
+		object[key] = child
+	}
+
+	return object
+}
+
+// Converts a resolved, non-group slog value into json data.
+func attrData(value slog.Value) interface{} {
+	switch value.Kind() {
+	case slog.KindString:
+		return value.String()
+	case slog.KindBool:
+		return value.Bool()
+	case slog.KindInt64:
+		return json.Number(strconv.FormatInt(value.Int64(), 10))
+	case slog.KindUint64:
+		return json.Number(strconv.FormatUint(value.Uint64(), 10))
+	case slog.KindFloat64:
+		if data, err := normalizeFloat(value.Float64(), 64); err == nil {
+			return data
+		}
+
+		return strconv.FormatFloat(value.Float64(), 'g', -1, 64)
+	case slog.KindDuration:
+		return json.Number(strconv.FormatInt(int64(value.Duration()), 10))
+	case slog.KindTime:
+		return value.Time().Format(time.RFC3339Nano)
+	}
+
+	if err, ok := value.Any().(error); ok {
+		return err.Error()
+	}
+
+	v, err := NewValue(value.Any())
+
+	if err != nil {
+		return fmt.Sprint(value.Any())
+	}
+
+	return v.data
+}
@@ -0,0 +1,90 @@
+package jason
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogValue(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{"user": {"name": "anton", "password": "secret", "roles": ["a", "b", "c"]}, "count": 2, "ratio": 0.5, "big": 1e400}`))
+	assert.True(err == nil, "failed to parse object")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropTime}))
+
+	logger.Info("request", "body", o)
+	expected := `{"level":"INFO","msg":"request","body":{"big":"1e400","count":2,"ratio":0.5,"user":{"name":"anton","password":"secret","roles":["a","b","c"]}}}` + "\n"
+	assert.True(buf.String() == expected, "objects should be logged as groups, got "+buf.String())
+
+	buf.Reset()
+	policy := RedactionPolicy{Keys: []string{"password"}}
+	logger.Info("request", "body", o.LogValuer(LogOptions{MaxDepth: 2, Redaction: &policy}))
+	expected = `{"level":"INFO","msg":"request","body":{"big":"1e400","count":2,"ratio":0.5,"user":{"name":"anton","password":"[REDACTED]","roles":"[...]"}}}` + "\n"
+	assert.True(buf.String() == expected, "depth and redaction should apply, got "+buf.String())
+
+	buf.Reset()
+	logger.Info("request", "body", o.LogValuer(LogOptions{MaxDepth: 1}))
+	expected = `{"level":"INFO","msg":"request","body":{"big":"1e400","count":2,"ratio":0.5,"user":"{...}"}}` + "\n"
+	assert.True(buf.String() == expected, "objects deeper than MaxDepth should be replaced, got "+buf.String())
+
+	roles, _ := o.GetValue("user", "roles")
+	buf.Reset()
+	logger.Info("roles", "roles", roles.LogValuer(LogOptions{MaxArrayLength: 2}))
+	expected = `{"level":"INFO","msg":"roles","roles":["a","b","... 1 more"]}` + "\n"
+	assert.True(buf.String() == expected, "long arrays should be cut, got "+buf.String())
+}
+
+func TestLogHandler(t *testing.T) {
+	assert := NewAssert(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(&buf, &LogHandlerOptions{Level: slog.LevelDebug, Redaction: &RedactionPolicy{Patterns: []string{"*token"}}}))
+
+	o, _ := NewObjectFromBytes([]byte(`{"id": 1, "tags": ["x"]}`))
+
+	logger.With("service", "api").WithGroup("req").Debug("<handled>",
+		"status", 200,
+		"elapsed", time.Second,
+		"err", errors.New("boom"),
+		"body", o,
+		"accessToken", "abc",
+		slog.Group("empty"),
+		"when", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	)
+
+	line := buf.String()
+	assert.True(strings.HasSuffix(line, "}\n") && strings.Count(line, "\n") == 1, "records should be written as one line")
+
+	var record map[string]interface{}
+	err := json.Unmarshal([]byte(line), &record)
+	assert.True(err == nil, "records should be valid json: "+line)
+
+	assert.True(strings.HasPrefix(line, `{"time":"`), "time should come first")
+	assert.True(strings.Contains(line, `"level":"DEBUG","msg":"<handled>","req":{`), "level and message should follow, got "+line)
+
+	expected := `"req":{"accessToken":"[REDACTED]","body":{"id":1,"tags":["x"]},"elapsed":1000000000,"err":"boom","status":200,"when":"2024-03-01T12:00:00Z"},"service":"api"}`
+	assert.True(strings.HasSuffix(line, expected+"\n"), "unexpected attributes in "+line)
+
+	buf.Reset()
+	slog.New(NewLogHandler(&buf, nil)).Debug("hidden")
+	assert.True(buf.Len() == 0, "records below the level should be dropped")
+
+	buf.Reset()
+	slog.New(NewLogHandler(&buf, &LogHandlerOptions{AddSource: true})).Info("source")
+	assert.True(strings.Contains(buf.String(), `"source":{"file":"`) && strings.Contains(buf.String(), "log_test.go"), "source should be added, got "+buf.String())
+}
+
+// Removes the time from records of slog.JSONHandler, so output can be compared.
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey && len(groups) == 0 {
+		return slog.Attr{}
+	}
+	return a
+}
@@ -0,0 +1,482 @@
+package jason
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error returned, wrapped with the offset, when MessagePack data is malformed
+var ErrInvalidMsgpack = errors.New("invalid msgpack")
+
+// The deepest nesting accepted when decoding MessagePack, the same limit encoding/json uses.
+const maxMsgpackDepth = 10000
+
+// MsgpackExtDecoder converts the data of a MessagePack extension type into a value.
+// It may return any data NewValue accepts.
+type MsgpackExtDecoder func(data []byte) (interface{}, error)
+
+var (
+	msgpackExtsMu sync.RWMutex
+	msgpackExts   = map[int8]MsgpackExtDecoder{-1: decodeMsgpackTimestamp}
+)
+
+// Registers the decoder of a MessagePack extension type for NewValueFromMsgpack, replacing any previous one.
+// The timestamp type -1 is registered by default and decodes to an RFC 3339 string, readable with Time().
+// Example:
+//		jason.RegisterMsgpackExt(1, func(data []byte) (interface{}, error) {
+//			return hex.EncodeToString(data), nil
+//		})
+func RegisterMsgpackExt(typ int8, decode MsgpackExtDecoder) {
+	msgpackExtsMu.Lock()
+	defer msgpackExtsMu.Unlock()
+
+	msgpackExts[typ] = decode
+}
+
+// Creates a new value from MessagePack data.
+// Integers and floats become numbers, kept exactly like json numbers, and binary data becomes a
+// standard base64 string that Bytes() decodes. Map keys must be strings, numbers, booleans or nil,
+// which are converted into strings. Extension types are decoded by the decoders registered with
+// RegisterMsgpackExt. Returns an error wrapping ErrInvalidMsgpack if b is malformed or holds
+// more than one value, and an error for unregistered extension types, NaN and infinities.
+// Example:
+//		v, err := jason.NewValueFromMsgpack(b)
+func NewValueFromMsgpack(b []byte) (*Value, error) {
+	d := &msgpackDecoder{b: b}
+	data, err := d.decode(0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos < len(b) {
+		return nil, d.errorf("unexpected data after the value")
+	}
+
+	return &Value{data, true}, nil
+}
+
+type msgpackDecoder struct {
+	b   []byte
+	pos int
+}
+
+func (d *msgpackDecoder) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("jason: %w: %s at offset %d", ErrInvalidMsgpack, fmt.Sprintf(format, args...), d.pos)
+}
+
+// Returns the next n bytes.
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || n > len(d.b)-d.pos {
+		return nil, d.errorf("unexpected end of data")
+	}
+
+	b := d.b[d.pos : d.pos+n]
+	d.pos += n
+
+	return b, nil
+}
+
+// Reads a big-endian unsigned integer of n bytes.
+func (d *msgpackDecoder) uint(n int) (uint64, error) {
+	b, err := d.read(n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+
+	return u, nil
+}
+
+// Reads a length of n bytes, checking that at least size bytes per element are left.
+func (d *msgpackDecoder) length(n int, size int) (int, error) {
+	u, err := d.uint(n)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if u > uint64(len(d.b)-d.pos)/uint64(size) {
+		return 0, d.errorf("length %d exceeds the data", u)
+	}
+
+	return int(u), nil
+}
+
+func (d *msgpackDecoder) decode(depth int) (interface{}, error) {
+	if depth > maxMsgpackDepth {
+		return nil, d.errorf("exceeded max depth")
+	}
+
+	b, err := d.read(1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := b[0]; {
+	case c <= 0x7f:
+		return json.Number(strconv.Itoa(int(c))), nil
+	case c >= 0xe0:
+		return json.Number(strconv.Itoa(int(int8(c)))), nil
+	case c >= 0x80 && c <= 0x8f:
+		return d.decodeMap(int(c&0x0f), depth)
+	case c >= 0x90 && c <= 0x9f:
+		return d.decodeArray(int(c&0x0f), depth)
+	case c >= 0xa0 && c <= 0xbf:
+		return d.decodeString(int(c & 0x1f))
+	}
+
+	switch c := b[0]; c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.length(1<<(c-0xc4), 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data, _ := d.read(n)
+
+		return base64.StdEncoding.EncodeToString(data), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.length(1<<(c-0xc7), 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return d.decodeExt(n + 1)
+	case 0xca:
+		u, err := d.uint(4)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return normalizeFloat(float64(math.Float32frombits(uint32(u))), 32)
+	case 0xcb:
+		u, err := d.uint(8)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return normalizeFloat(math.Float64frombits(u), 64)
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (c - 0xcc))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Number(strconv.FormatUint(u, 10)), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		u, err := d.uint(n)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Sign extend from the size of the integer.
+		shift := 64 - 8*n
+
+		return json.Number(strconv.FormatInt(int64(u<<shift)>>shift, 10)), nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1<<(c-0xd4) + 1)
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.length(1<<(c-0xd9), 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return d.decodeString(n)
+	case 0xdc, 0xdd:
+		n, err := d.length(2<<(c-0xdc), 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return d.decodeArray(n, depth)
+	case 0xde, 0xdf:
+		n, err := d.length(2<<(c-0xde), 2)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return d.decodeMap(n, depth)
+	}
+
+	d.pos--
+
+	return nil, d.errorf("invalid type byte 0x%02x", b[0])
+}
+
+func (d *msgpackDecoder) decodeString(n int) (interface{}, error) {
+	b, err := d.read(n)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.ToValidUTF8(string(b), "\ufffd"), nil
+}
+
+func (d *msgpackDecoder) decodeArray(n int, depth int) (interface{}, error) {
+	array := make([]interface{}, n)
+
+	for i := range array {
+		element, err := d.decode(depth + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		array[i] = element
+	}
+
+	return array, nil
+}
+
+func (d *msgpackDecoder) decodeMap(n int, depth int) (interface{}, error) {
+	m := make(map[string]interface{}, n)
+
+	for i := 0; i < n; i++ {
+		start := d.pos
+		keyData, err := d.decode(depth + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := scalarKey(keyData)
+
+		if !ok {
+			d.pos = start
+			return nil, d.errorf("map keys must be strings, numbers, booleans or nil")
+		}
+
+		if m[key], err = d.decode(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Decodes an extension of n bytes, including its type.
+func (d *msgpackDecoder) decodeExt(n int) (interface{}, error) {
+	start := d.pos
+	b, err := d.read(n)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typ := int8(b[0])
+
+	msgpackExtsMu.RLock()
+	decode, ok := msgpackExts[typ]
+	msgpackExtsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("jason: unregistered msgpack extension type %d at offset %d", typ, start)
+	}
+
+	data, err := decode(b[1:])
+
+	if err != nil {
+		return nil, fmt.Errorf("jason: msgpack extension type %d at offset %d: %w", typ, start, err)
+	}
+
+	return normalize(data)
+}
+
+// Decodes the timestamp extension type into an RFC 3339 string.
+func decodeMsgpackTimestamp(data []byte) (interface{}, error) {
+	var seconds, nanoseconds int64
+
+	switch len(data) {
+	case 4:
+		seconds = int64(binary.BigEndian.Uint32(data))
+	case 8:
+		u := binary.BigEndian.Uint64(data)
+		seconds, nanoseconds = int64(u&(1<<34-1)), int64(u>>34)
+	case 12:
+		nanoseconds = int64(binary.BigEndian.Uint32(data))
+		seconds = int64(binary.BigEndian.Uint64(data[4:]))
+	default:
+		return nil, fmt.Errorf("invalid timestamp length %d", len(data))
+	}
+
+	if nanoseconds > 999999999 {
+		return nil, fmt.Errorf("invalid timestamp nanoseconds %d", nanoseconds)
+	}
+
+	return time.Unix(seconds, nanoseconds).UTC().Format(time.RFC3339Nano), nil
+}
+
+// Marshal into MessagePack.
+// Numbers are written as the smallest integer type that holds them, or as 64-bit floats
+// if they have a fraction or an exponent. Object keys are sorted, like Marshal.
+// Strings are always written as strings, also if they came from binary data or extensions.
+// Returns ErrOutOfRange for integers that don't fit in 64 bits and floats that overflow.
+// Example:
+//		b, err := v.MarshalMsgpack()
+func (v *Value) MarshalMsgpack() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := encodeMsgpack(&buf, v.data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeMsgpack(buf *bytes.Buffer, data interface{}) error {
+	switch data := data.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if data {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case string:
+		writeMsgpackHeader(buf, len(data), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		buf.WriteString(data)
+	case json.Number:
+		return encodeMsgpackNumber(buf, data)
+	case []interface{}:
+		writeMsgpackHeader(buf, len(data), 0x90, 16, 0, 0xdc, 0xdd)
+
+		for _, element := range data {
+			if err := encodeMsgpack(buf, element); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		writeMsgpackHeader(buf, len(data), 0x80, 16, 0, 0xde, 0xdf)
+
+		for _, key := range slices.Sorted(maps.Keys(data)) {
+			encodeMsgpack(buf, key)
+
+			if err := encodeMsgpack(buf, data[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("jason: unsupported type %T", data)
+	}
+
+	return nil
+}
+
+// Writes the header of a string, array or map of length n. Lengths below fixMax use the fix
+// format, and the 8-bit format is skipped if its type byte is 0.
+func writeMsgpackHeader(buf *bytes.Buffer, n int, fix byte, fixMax int, type8, type16, type32 byte) {
+	switch {
+	case n < fixMax:
+		buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint8 && type8 != 0:
+		buf.Write([]byte{type8, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(type16)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(type32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+func encodeMsgpackNumber(buf *bytes.Buffer, n json.Number) error {
+	s := string(n)
+
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			writeMsgpackInt(buf, i)
+			return nil
+		}
+
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			buf.WriteByte(0xcf)
+			buf.Write(binary.BigEndian.AppendUint64(nil, u))
+			return nil
+		}
+
+		return fmt.Errorf("jason: number %s: %w", s, ErrOutOfRange)
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return fmt.Errorf("jason: number %s: %w", s, ErrOutOfRange)
+		}
+
+		return ErrNotNumber
+	}
+
+	buf.WriteByte(0xcb)
+	buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+
+	return nil
+}
+
+// Writes an integer in the smallest format that holds it.
+func writeMsgpackInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i <= math.MaxInt8:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(int8(i)))
+	case i >= 0 && i <= math.MaxUint8:
+		buf.Write([]byte{0xcc, byte(i)})
+	case i >= 0 && i <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(i)))
+	case i >= 0 && i <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+	case i >= 0:
+		buf.WriteByte(0xcf)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	case i >= math.MinInt8:
+		buf.Write([]byte{0xd0, byte(i)})
+	case i >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(i)))
+	case i >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+	default:
+		buf.WriteByte(0xd3)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	}
+}
@@ -0,0 +1,167 @@
+package jason
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsgpackDecode(t *testing.T) {
+	tests := []struct {
+		msgpack string // Hex
+		json    string
+	}{
+		{"c0", `null`},
+		{"c3", `true`},
+		{"7f", `127`},
+		{"e0", `-32`},
+		{"cdffff", `65535`},
+		{"cfffffffffffffffff", `18446744073709551615`},
+		{"d0ff", `-1`},
+		{"d3ffffffffffffff00", `-256`},
+		{"cb3ff8000000000000", `1.5`},
+		{"ca3fc00000", `1.5`},
+		{"cb3fb999999999999a", `0.1`},
+		{"a3616263", `"abc"`},
+		{"d903616263", `"abc"`},
+		{"c403010203", `"AQID"`},
+		{"93010203", `[1,2,3]`},
+		{"dc0002c2c0", `[false,null]`},
+		{"82a16101a1629201a162", `{"a":1,"b":[1,"b"]}`},
+		{"8201a16fc3a174", `{"1":"o","true":"t"}`},
+		{"d6ff00000000", `"1970-01-01T00:00:00Z"`},
+		{"d7ff000000040000000a", `"1970-01-01T00:00:10.000000001Z"`},
+	}
+
+	for _, test := range tests {
+		b, _ := hex.DecodeString(test.msgpack)
+		v, err := NewValueFromMsgpack(b)
+
+		if err != nil {
+			t.Errorf("%s: %v", test.msgpack, err)
+			continue
+		}
+
+		if s, _ := v.Marshal(); string(s) != test.json {
+			t.Errorf("%s: expected %s, got %s", test.msgpack, test.json, s)
+		}
+	}
+}
+
+func TestMsgpackEncode(t *testing.T) {
+	tests := []struct {
+		json    string
+		msgpack string // Hex
+	}{
+		{`null`, "c0"},
+		{`[true, false]`, "92c3c2"},
+		{`[0, 127, 128, 255, 256, 65536, 4294967296]`, "97007fcc80ccffcd0100ce00010000cf0000000100000000"},
+		{`[-1, -32, -33, -129, -32769, -2147483649]`, "96ffe0d0dfd1ff7fd2ffff7fffd3ffffffff7fffffff"},
+		{`18446744073709551615`, "cfffffffffffffffff"},
+		{`[1.5, 1e2]`, "92cb3ff8000000000000cb4059000000000000"},
+		{`{"b": "x", "a": {}}`, "82a16180a162a178"},
+		{`"` + strings.Repeat("x", 32) + `"`, "d920" + strings.Repeat("78", 32)},
+	}
+
+	for _, test := range tests {
+		v, err := NewValueFromBytes([]byte(test.json))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := v.MarshalMsgpack()
+
+		if err != nil {
+			t.Errorf("%s: %v", test.json, err)
+			continue
+		}
+
+		if s := hex.EncodeToString(b); s != test.msgpack {
+			t.Errorf("%s: expected %s, got %s", test.json, test.msgpack, s)
+		}
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{
+		"id": 9007199254740993,
+		"price": 19.99,
+		"tags": ["a", "b"],
+		"nested": {"ok": true, "none": null},
+		"long": "` + strings.Repeat("y", 70000) + `"
+	}`))
+	assert.True(err == nil, "failed to parse object")
+
+	b, err := o.MarshalMsgpack()
+	assert.True(err == nil, "object should encode")
+
+	v, err := NewValueFromMsgpack(b)
+	assert.True(err == nil, "encoded object should decode")
+
+	expected, _ := o.Marshal()
+	actual, _ := v.Marshal()
+	assert.True(bytes.Equal(expected, actual), "round trip should keep the value")
+
+	object, _ := v.Object()
+	id, _ := object.GetValue("id")
+	s, _ := id.Marshal()
+	assert.True(string(s) == "9007199254740993", "large integers should stay exact")
+}
+
+func TestMsgpackBinaryAndExt(t *testing.T) {
+	assert := NewAssert(t)
+
+	v, err := NewValueFromMsgpack([]byte{0xc4, 0x02, 0xfb, 0xff})
+	assert.True(err == nil, "binary should decode")
+
+	b, err := v.Bytes()
+	assert.True(err == nil && bytes.Equal(b, []byte{0xfb, 0xff}), "binary should be readable with Bytes")
+
+	ts, err := NewValueFromMsgpack([]byte{0xd6, 0xff, 0x00, 0x00, 0x00, 0x3c})
+	assert.True(err == nil, "timestamps should decode")
+
+	tm, err := ts.Time()
+	assert.True(err == nil && tm.Equal(time.Unix(60, 0)), "timestamps should be readable with Time")
+
+	_, err = NewValueFromMsgpack([]byte{0xd4, 0x42, 0x01})
+	assert.True(err != nil, "unregistered extension types should return an error")
+
+	RegisterMsgpackExt(0x42, func(data []byte) (interface{}, error) {
+		return map[string]interface{}{"ext": hex.EncodeToString(data)}, nil
+	})
+
+	ext, err := NewValueFromMsgpack([]byte{0xd4, 0x42, 0x01})
+	assert.True(err == nil, "registered extension types should decode")
+
+	s, _ := ext.Marshal()
+	assert.True(string(s) == `{"ext":"01"}`, "extension values should be normalized")
+}
+
+func TestMsgpackErrors(t *testing.T) {
+	for _, input := range []string{"", "c1", "a3616263ff", "a5616263", "dd7fffffff", "81c0", "81900101", "cb7ff8000000000000"} {
+		b, _ := hex.DecodeString(input)
+
+		if _, err := NewValueFromMsgpack(b); err == nil {
+			t.Errorf("%q should return an error", input)
+		}
+	}
+
+	_, err := NewValueFromMsgpack([]byte{0xc1})
+	if !errors.Is(err, ErrInvalidMsgpack) {
+		t.Errorf("malformed data should return ErrInvalidMsgpack, got %v", err)
+	}
+
+	v, _ := NewValueFromBytes([]byte(`[18446744073709551616, 1e400]`))
+	elements, _ := v.Array()
+	for _, e := range elements {
+		if _, err := e.MarshalMsgpack(); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("%s should return ErrOutOfRange, got %v", e.Interface(), err)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package jason
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Error values returned when converting numbers
+var (
+	ErrNotInteger = errors.New("not an integer")
+	ErrOutOfRange = errors.New("number out of range")
+	ErrBitSize    = errors.New("bit size must be between 0 and 64")
+)
+
+// The largest number of decimal digits BigInt will produce, and the largest scale Decimal accepts.
+// Guards against literals like 1e1000000000 that would take gigabytes to expand.
+const maxIntegerDigits = 10000
+
+// Decimal is an exact decimal number that keeps the scale it was written with,
+// so that 12.50 and 12.5 stay distinguishable.
+// The number equals Unscaled × 10^-Scale. A negative scale means trailing zeros
+// written as an exponent, like 15e2.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int
+}
+
+// Returns the decimal in plain notation, like 12.50.
+// Negative scales are written with an exponent, like 15e2.
+func (d Decimal) String() string {
+	if d.Unscaled == nil {
+		return "0"
+	}
+
+	if d.Scale < 0 {
+		return d.Unscaled.String() + "e" + strconv.Itoa(-d.Scale)
+	}
+
+	digits := new(big.Int).Abs(d.Unscaled).String()
+	sign := ""
+	if d.Unscaled.Sign() < 0 {
+		sign = "-"
+	}
+
+	if d.Scale == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= d.Scale {
+		digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+	}
+
+	point := len(digits) - d.Scale
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Returns the decimal as an exact fraction.
+func (d Decimal) Rat() *big.Rat {
+	r := new(big.Rat)
+
+	if d.Unscaled == nil {
+		return r
+	}
+
+	r.SetInt(d.Unscaled)
+
+	scale := d.Scale
+	if scale < 0 {
+		scale = -scale
+	}
+
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+
+	if d.Scale > 0 {
+		return r.Quo(r, new(big.Rat).SetInt(pow))
+	}
+
+	return r.Mul(r, new(big.Rat).SetInt(pow))
+}
+
+// Attempts to typecast the current value into a big.Int.
+// Returns error if the current value is not a json number, ErrNotInteger if it has a fractional part
+// and ErrOutOfRange if it has more than 10000 digits. Integral numbers like 1.0 and 1e3 are accepted.
+// Example:
+//		id, err := v.BigInt()
+func (v *Value) BigInt() (*big.Int, error) {
+	n, err := v.Number()
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := parseDecimal(n)
+
+	if !d.valid {
+		return nil, ErrNotNumber
+	}
+
+	if d.digits == "" {
+		return new(big.Int), nil
+	}
+
+	if d.exp < len(d.digits) {
+		return nil, ErrNotInteger
+	}
+
+	if d.exp > maxIntegerDigits {
+		return nil, ErrOutOfRange
+	}
+
+	s := d.digits + strings.Repeat("0", d.exp-len(d.digits))
+	if d.neg {
+		s = "-" + s
+	}
+
+	i, _ := new(big.Int).SetString(s, 10)
+
+	return i, nil
+}
+
+// Attempts to typecast the current value into a big.Float.
+// The precision is large enough to hold every digit of the number, so integers are exact.
+// Returns error if the current value is not a json number and ErrOutOfRange if it exceeds the exponent range of big.Float.
+// Example:
+//		amount, err := v.BigFloat()
+func (v *Value) BigFloat() (*big.Float, error) {
+	n, err := v.Number()
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := parseDecimal(n)
+
+	if !d.valid {
+		return nil, ErrNotNumber
+	}
+
+	// A decimal digit needs less than four bits.
+	prec := uint(4*len(d.digits) + 64)
+
+	f, _, err := big.ParseFloat(string(n), 10, prec, big.ToNearestEven)
+
+	// Exponents beyond the range of big.Float become infinite or zero.
+	if err != nil || f.IsInf() || (f.Sign() == 0 && d.digits != "") {
+		return nil, ErrOutOfRange
+	}
+
+	return f, nil
+}
+
+// Attempts to typecast the current value into a uint64.
+// Integral numbers like 1e3 and 10.0 are accepted.
+// Returns error if the current value is not a json number, or an *IntegerError wrapping ErrNotInteger
+// if it has a fractional part and ErrOutOfRange if it is negative or too large.
+// Example:
+//		id, err := v.Uint64()
+func (v *Value) Uint64() (uint64, error) {
+	return v.Unsigned(IntegralNumbers, 64)
+}
+
+// Attempts to typecast the current value into a Decimal that keeps the scale of the literal.
+// Returns error if the current value is not a json number and ErrOutOfRange if the exponent makes the scale larger than 10000 in either direction.
+// Example:
+//		price, err := v.Decimal() // 12.50 gives Decimal{1250, 2}
+func (v *Value) Decimal() (Decimal, error) {
+	n, err := v.Number()
+
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	if !parseDecimal(n).valid {
+		return Decimal{}, ErrNotNumber
+	}
+
+	s := string(n)
+	scale := 0
+
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+
+		if err != nil {
+			return Decimal{}, ErrOutOfRange
+		}
+
+		scale = -exp
+		s = s[:i]
+	}
+
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		scale += len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+
+	if scale > maxIntegerDigits || scale < -maxIntegerDigits {
+		return Decimal{}, ErrOutOfRange
+	}
+
+	unscaled, ok := new(big.Int).SetString(s, 10)
+
+	if !ok {
+		return Decimal{}, ErrNotNumber
+	}
+
+	return Decimal{unscaled, scale}, nil
+}
+
+// Gets the value at key path and attempts to typecast the value into a big.Int.
+// Returns error if the value is not an integral json number.
+// Example:
+//		id, err := GetBigInt("account", "id")
+func (v *Object) GetBigInt(keys ...string) (*big.Int, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return child.BigInt()
+}
+
+// Gets the value at key path and attempts to typecast the value into a big.Float.
+// Returns error if the value is not a json number.
+// Example:
+//		amount, err := GetBigFloat("payment", "amount")
+func (v *Object) GetBigFloat(keys ...string) (*big.Float, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return child.BigFloat()
+}
+
+// Gets the value at key path and attempts to typecast the value into a uint64.
+// Returns error like GetUnsigned.
+// Example:
+//		id, err := GetUint64("account", "id")
+func (v *Object) GetUint64(keys ...string) (uint64, error) {
+	return v.GetUnsigned(IntegralNumbers, 64, keys...)
+}
+
+// Gets the value at key path and attempts to typecast the value into a Decimal.
+// Returns error if the value is not a json number.
+// Example:
+//		price, err := GetDecimal("payment", "amount")
+func (v *Object) GetDecimal(keys ...string) (Decimal, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return Decimal{}, err
+	}
+
+	return child.Decimal()
+}
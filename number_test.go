@@ -0,0 +1,119 @@
+package jason
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestBigNumbers(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{
+		"id": 170141183460469231731687303715884105727,
+		"max": 18446744073709551615,
+		"exp": 1.5e3,
+		"integral": 2.0,
+		"price": 12.50,
+		"small": -0.05,
+		"negative": -1,
+		"fraction": 0.5,
+		"huge": 1e100000,
+		"string": "1"
+	}`))
+	assert.True(err == nil, "failed to parse object")
+
+	id, err := o.GetBigInt("id")
+	assert.True(err == nil && id.String() == "170141183460469231731687303715884105727", "big integers should be exact")
+
+	exp, err := o.GetBigInt("exp")
+	assert.True(err == nil && exp.Int64() == 1500, "integral exponent notation should be accepted")
+
+	integral, err := o.GetBigInt("integral")
+	assert.True(err == nil && integral.Int64() == 2, "integral decimals should be accepted")
+
+	_, err = o.GetBigInt("fraction")
+	assert.True(err == ErrNotInteger, "fractions should not truncate")
+
+	_, err = o.GetBigInt("huge")
+	assert.True(err == ErrOutOfRange, "huge exponents should not be expanded")
+
+	_, err = o.GetBigInt("string")
+	assert.True(err == ErrNotNumber, "strings are not numbers")
+
+	f, err := o.GetBigFloat("id")
+	i, _ := f.Int(nil)
+	assert.True(err == nil && i.Cmp(id) == 0, "big floats should hold big integers exactly")
+
+	f, err = o.GetBigFloat("huge")
+	assert.True(err == nil && f.Cmp(new(big.Float).SetFloat64(1e300)) > 0, "big floats should exceed float64")
+
+	max, err := o.GetUint64("max")
+	assert.True(err == nil && max == 18446744073709551615, "uint64 max should fit")
+
+	u, err := o.GetUint64("exp")
+	assert.True(err == nil && u == 1500, "uint64 should accept exponent notation")
+
+	_, err = o.GetUint64("negative")
+	assert.True(errors.Is(err, ErrOutOfRange), "negative numbers are out of range for uint64")
+
+	_, err = o.GetUint64("id")
+	assert.True(errors.Is(err, ErrOutOfRange), "128-bit numbers are out of range for uint64")
+
+	_, err = o.GetUint64("fraction")
+	assert.True(errors.Is(err, ErrNotInteger), "fractions are not integers")
+}
+
+func TestDecimal(t *testing.T) {
+	cases := []struct {
+		literal  string
+		unscaled string
+		scale    int
+		rat      string
+	}{
+		{"12.50", "1250", 2, "25/2"},
+		{"-0.05", "-5", 2, "-1/20"},
+		{"1.5e3", "15", -2, "1500/1"},
+		{"1E-2", "1", 2, "1/100"},
+		{"0", "0", 0, "0/1"},
+		{"100", "100", 0, "100/1"},
+	}
+
+	for _, c := range cases {
+		v, err := NewValueFromBytes([]byte(c.literal))
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", c.literal, err)
+		}
+
+		d, err := v.Decimal()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.literal, err)
+			continue
+		}
+
+		if d.Unscaled.String() != c.unscaled || d.Scale != c.scale {
+			t.Errorf("%s: got %s scale %d, expected %s scale %d", c.literal, d.Unscaled, d.Scale, c.unscaled, c.scale)
+		}
+
+		if d.Rat().String() != c.rat {
+			t.Errorf("%s: got fraction %s, expected %s", c.literal, d.Rat(), c.rat)
+		}
+	}
+
+	for literal, expected := range map[string]string{"12.50": "12.50", "-0.05": "-0.05", "1.5e3": "15e2", "7": "7"} {
+		v, _ := NewValueFromBytes([]byte(literal))
+		d, _ := v.Decimal()
+
+		if d.String() != expected {
+			t.Errorf("%s: got string %s, expected %s", literal, d, expected)
+		}
+	}
+
+	for _, literal := range []string{"1e-1000000000", "1e1000000000", "0.5e-10000"} {
+		v, _ := NewValueFromBytes([]byte(literal))
+
+		if _, err := v.Decimal(); err != ErrOutOfRange {
+			t.Errorf("%s: expected ErrOutOfRange, got %v", literal, err)
+		}
+	}
+}
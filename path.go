@@ -0,0 +1,126 @@
+package jason
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error value returned when an array index in a path is out of range
+var ErrIndexOutOfRange = errors.New("index out of range")
+
+// Path identifies a location in a JSON document.
+// Each element is either a string, naming an object key, or an int, indexing an array.
+// An empty path refers to the document root.
+type Path []interface{}
+
+// Returns the path in JSONPath notation, like $.friends[0]['first name'].
+func (p Path) String() string {
+	var b strings.Builder
+
+	b.WriteString("$")
+
+	for _, element := range p {
+		switch element := element.(type) {
+		case int:
+			b.WriteString("[" + strconv.Itoa(element) + "]")
+		case string:
+			if isIdentifier(element) {
+				b.WriteString("." + element)
+			} else {
+				b.WriteString("['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(element) + "']")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// Returns the path as a JSON Pointer (RFC 6901), like /friends/0/first name.
+func (p Path) Pointer() string {
+	var b strings.Builder
+
+	for _, element := range p {
+		b.WriteString("/")
+
+		switch element := element.(type) {
+		case int:
+			b.WriteString(strconv.Itoa(element))
+		case string:
+			b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(element))
+		}
+	}
+
+	return b.String()
+}
+
+// Returns a copy of the path with one more element appended.
+// The copy never shares its backing array with p.
+func (p Path) Append(element interface{}) Path {
+	child := make(Path, len(p), len(p)+1)
+	copy(child, p)
+
+	return append(child, element)
+}
+
+// Returns the key a path element names in objects, and the index it names in arrays or -1.
+// String elements also index arrays if they are decimal numbers without leading zeros.
+func pathElement(element interface{}) (key string, index int, err error) {
+	switch element := element.(type) {
+	case string:
+		// Decimal tokens without leading zeros may index arrays.
+		if i, err := strconv.Atoi(element); err == nil && i >= 0 && strconv.Itoa(i) == element {
+			return element, i, nil
+		}
+
+		return element, -1, nil
+	case int:
+		return strconv.Itoa(element), element, nil
+	}
+
+	return "", -1, fmt.Errorf("jason: invalid path element %v of type %T", element, element)
+}
+
+// Converts a key path into a Path.
+func keyPath(keys []string) Path {
+	path := make(Path, len(keys))
+	for i, key := range keys {
+		path[i] = key
+	}
+
+	return path
+}
+
+// Records the location in an *IntegerError or *FormatError.
+func withPath(err error, path Path) error {
+	var integerErr *IntegerError
+	var formatErr *FormatError
+
+	switch {
+	case errors.As(err, &integerErr):
+		integerErr.Path = path
+	case errors.As(err, &formatErr):
+		formatErr.Path = path
+	}
+
+	return err
+}
+
+// Reports whether s can be written after a dot in JSONPath notation.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, c := range s {
+		switch {
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80:
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,35 @@
+package jason
+
+import (
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	assert := NewAssert(t)
+
+	p := Path{"friends", 0, "first name", "a/b~c"}
+
+	assert.True(p.String() == `$.friends[0]['first name']['a/b~c']`, "unexpected path string "+p.String())
+	assert.True(p.Pointer() == `/friends/0/first name/a~1b~0c`, "unexpected pointer "+p.Pointer())
+	assert.True(Path{}.String() == "$" && Path{}.Pointer() == "", "the empty path should refer to the root")
+
+	child := p[:1].Append("x")
+	other := p[:1].Append("y")
+	assert.True(child[1] == "x" && other[1] == "y", "appended paths should not share elements")
+}
+
+func TestPathElement(t *testing.T) {
+	assert := NewAssert(t)
+
+	key, index, err := pathElement("3")
+	assert.True(err == nil && key == "3" && index == 3, "decimal strings should index arrays")
+
+	key, index, err = pathElement("03")
+	assert.True(err == nil && key == "03" && index == -1, "leading zeros should not index arrays")
+
+	key, index, err = pathElement(1)
+	assert.True(err == nil && key == "1" && index == 1, "ints should index arrays")
+
+	_, _, err = pathElement(1.5)
+	assert.True(err != nil, "other types should return an error")
+}
@@ -0,0 +1,135 @@
+package jason
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/antonholmquist/jason/internal/jsonpath"
+)
+
+// The mask used when a RedactionPolicy doesn't set one.
+const DefaultMask = "[REDACTED]"
+
+// RedactionPolicy selects sensitive values to hide, for example before logging a document.
+// A value is redacted if its key matches Keys or Patterns, or if its location matches Paths.
+// Redacted objects and arrays are replaced as a whole.
+type RedactionPolicy struct {
+	Keys     []string // Object keys to redact, compared case-insensitively, like "password"
+	Patterns []string // Glob patterns for object keys as in path.Match, compared case-insensitively, like "*token*"
+	Paths    []string // JSONPath expressions, like "$.user.ssn" or "$..card.number"
+	Mask     string   // Replaces redacted values, DefaultMask if empty
+
+	// If set, redacted values are replaced with "hmac:" and a keyed hash of the value instead of the mask,
+	// so that equal values can be correlated across log lines without being revealed.
+	Salt []byte
+}
+
+// Returns a copy of the value with the values selected by the policy replaced, leaving v untouched.
+// Returns error if a pattern or JSONPath expression of the policy is invalid.
+// Example:
+//		safe, err := v.Redact(jason.RedactionPolicy{Keys: []string{"password"}, Patterns: []string{"*token*"}})
+func (v *Value) Redact(policy RedactionPolicy) (*Value, error) {
+	keys := make(map[string]bool, len(policy.Keys))
+	for _, key := range policy.Keys {
+		keys[strings.ToLower(key)] = true
+	}
+
+	patterns := make([]string, len(policy.Patterns))
+	for i, pattern := range policy.Patterns {
+		patterns[i] = strings.ToLower(pattern)
+
+		if _, err := path.Match(patterns[i], ""); err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+	}
+
+	pointers := make(map[string]bool)
+	for _, expr := range policy.Paths {
+		q, err := jsonpath.Compile(expr)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range q.Select(v.data) {
+			pointers[Path(node.Path).Pointer()] = true
+		}
+	}
+
+	mask := policy.Mask
+	if mask == "" {
+		mask = DefaultMask
+	}
+
+	redacted := func(p Path) bool {
+		if pointers[p.Pointer()] {
+			return true
+		}
+
+		if len(p) == 0 {
+			return false
+		}
+
+		key, ok := p[len(p)-1].(string)
+		if !ok {
+			return false
+		}
+
+		key = strings.ToLower(key)
+
+		if keys[key] {
+			return true
+		}
+
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, key); matched {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return Transform(v, func(p Path, child *Value) Edit {
+		if !redacted(p) {
+			return Keep()
+		}
+
+		if policy.Salt == nil {
+			return Replace(&Value{mask, true})
+		}
+
+		h := hmac.New(sha256.New, policy.Salt)
+		child.Hash(h)
+
+		return Replace(&Value{"hmac:" + hex.EncodeToString(h.Sum(nil)[:16]), true})
+	}), nil
+}
+
+// Returns the object as a json string with the values selected by the policy redacted, for use in log lines.
+// If the policy is invalid, the whole object is masked so that nothing leaks.
+// Example:
+//		log.Println(o.RedactedString(policy))
+func (v *Object) RedactedString(policy RedactionPolicy) string {
+	redacted, err := v.Value.Redact(policy)
+
+	if err != nil {
+		redacted = &Value{policy.Mask, true}
+
+		if policy.Mask == "" {
+			redacted.data = DefaultMask
+		}
+	}
+
+	b, err := redacted.Marshal()
+
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
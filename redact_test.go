@@ -0,0 +1,62 @@
+package jason
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{
+		"user": {"name": "anton", "Password": "secret", "ssn": "123"},
+		"accessToken": "abc",
+		"cards": [{"number": "4111", "last4": "1111"}],
+		"credentials": {"key": "k", "secret": "s"}
+	}`))
+	assert.True(err == nil, "failed to parse object")
+
+	policy := RedactionPolicy{
+		Keys:     []string{"password", "credentials"},
+		Patterns: []string{"*token"},
+		Paths:    []string{"$.user.ssn", "$.cards[*].number"},
+	}
+
+	s := o.RedactedString(policy)
+	expected := `{"accessToken":"[REDACTED]","cards":[{"last4":"1111","number":"[REDACTED]"}],"credentials":"[REDACTED]","user":{"Password":"[REDACTED]","name":"anton","ssn":"[REDACTED]"}}`
+	assert.True(s == expected, "unexpected redacted string "+s)
+
+	password, _ := o.GetString("user", "Password")
+	assert.True(password == "secret", "the object should be untouched")
+
+	policy.Mask = "***"
+	redacted, err := o.Redact(policy)
+	assert.True(err == nil, "valid policy should not return an error")
+	b, _ := redacted.Marshal()
+	assert.True(strings.Contains(string(b), `"accessToken":"***"`), "custom masks should be used")
+
+	salted := RedactionPolicy{Keys: []string{"secret", "Password"}, Salt: []byte("salt")}
+	first, _ := o.Redact(salted)
+	second, _ := o.Redact(salted)
+	firstObject, _ := first.Object()
+	hash, _ := firstObject.GetString("user", "Password")
+	assert.True(strings.HasPrefix(hash, "hmac:") && len(hash) == 37, "salted redaction should replace values with a hash, got "+hash)
+	assert.True(first.Equal(second), "salted redaction should be deterministic")
+
+	other, _ := o.Redact(RedactionPolicy{Keys: []string{"Password"}, Salt: []byte("pepper")})
+	otherObject, _ := other.Object()
+	otherHash, _ := otherObject.GetString("user", "Password")
+	assert.True(hash != otherHash, "different salts should give different hashes")
+
+	credentialSecret, _ := firstObject.GetString("credentials", "secret")
+	assert.True(credentialSecret != hash, "different values should give different hashes")
+
+	_, err = o.Redact(RedactionPolicy{Paths: []string{"$["}})
+	assert.True(err != nil, "invalid paths should return an error")
+
+	_, err = o.Redact(RedactionPolicy{Patterns: []string{"[token"}})
+	assert.True(err != nil, "invalid patterns should return an error")
+
+	s = o.RedactedString(RedactionPolicy{Patterns: []string{"[token"}})
+	assert.True(s == `"[REDACTED]"`, "invalid policies should mask everything")
+}
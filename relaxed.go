@@ -0,0 +1,506 @@
+package jason
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ParseOption changes how the NewValueFrom* and NewObjectFrom* constructors read their input.
+type ParseOption interface {
+	apply(*parseOptions)
+}
+
+type parseOptions struct {
+	syntax Syntax
+}
+
+func parseOptionsOf(options []ParseOption) parseOptions {
+	var o parseOptions
+	for _, option := range options {
+		option.apply(&o)
+	}
+
+	return o
+}
+
+// Syntax is a dialect of json accepted by the constructors. It is a ParseOption.
+type Syntax int
+
+const (
+	// Strict RFC 8259 json, the default.
+	StrictJSON Syntax = iota
+
+	// Json with // and /* */ comments and trailing commas, as used by many editor configs.
+	JSONC
+
+	// JSON5 (https://json5.org), which adds single-quoted and multi-line strings, unquoted keys,
+	// hexadecimal numbers, leading and trailing decimal points and explicit plus signs to JSONC.
+	// Infinity and NaN are rejected, since they can't be represented in json.
+	JSON5
+)
+
+func (s Syntax) apply(o *parseOptions) {
+	o.syntax = s
+}
+
+// SyntaxError is returned when input isn't valid JSONC or JSON5.
+type SyntaxError struct {
+	Offset  int64 // Byte offset of the error
+	Line    int   // 1-based line of the error
+	Column  int   // 1-based column of the error
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// The deepest nesting accepted, the same limit encoding/json uses.
+const maxRelaxedDepth = 10000
+
+// Parses the whole input of a reader in a relaxed syntax. Unlike strict parsing,
+// data after the value is an error, since config files hold a single value.
+func newRelaxedValue(reader io.Reader, o parseOptions) (*Value, error) {
+	b, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := &relaxedParser{src: string(b), json5: o.syntax == JSON5}
+	j := new(Value)
+
+	if j.data, err = p.parseValue(0); err != nil {
+		return j, err
+	}
+
+	if err := p.skipSpace(); err != nil {
+		return j, err
+	}
+
+	if p.pos < len(p.src) {
+		return j, p.errorf("unexpected data after the value")
+	}
+
+	j.exists = true
+
+	return j, nil
+}
+
+type relaxedParser struct {
+	src   string
+	pos   int
+	json5 bool
+}
+
+func (p *relaxedParser) errorf(format string, args ...interface{}) error {
+	line := 1 + strings.Count(p.src[:p.pos], "\n")
+	column := p.pos - strings.LastIndexByte(p.src[:p.pos], '\n')
+
+	return &SyntaxError{Offset: int64(p.pos), Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *relaxedParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+
+	return 0
+}
+
+// Skips whitespace and comments.
+func (p *relaxedParser) skipSpace() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '\r' {
+				p.pos++
+			}
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+
+			if end < 0 {
+				return p.errorf("unclosed comment")
+			}
+
+			p.pos += end + 4
+		case p.json5:
+			r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+
+			if r != '\v' && r != '\f' && r != '\ufeff' && r != '\u2028' && r != '\u2029' && !unicode.Is(unicode.Zs, r) {
+				return nil
+			}
+
+			p.pos += size
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (p *relaxedParser) parseValue(depth int) (interface{}, error) {
+	if depth > maxRelaxedDepth {
+		return nil, p.errorf("exceeded max depth")
+	}
+
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+
+	switch c := p.peek(); {
+	case c == '{':
+		return p.parseObject(depth)
+	case c == '[':
+		return p.parseArray(depth)
+	case c == '"' || (c == '\'' && p.json5):
+		return p.parseString()
+	case c == 0:
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	for word, data := range map[string]interface{}{"true": true, "false": false, "null": nil} {
+		if strings.HasPrefix(p.src[p.pos:], word) {
+			p.pos += len(word)
+			return data, nil
+		}
+	}
+
+	return p.parseNumber()
+}
+
+var (
+	strictNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?`)
+	json5Number  = regexp.MustCompile(`^[-+]?(0[xX][0-9a-fA-F]+|Infinity|NaN|(0|[1-9][0-9]*)(\.[0-9]*)?([eE][-+]?[0-9]+)?|\.[0-9]+([eE][-+]?[0-9]+)?)`)
+)
+
+func (p *relaxedParser) parseNumber() (interface{}, error) {
+	if !p.json5 {
+		s := strictNumber.FindString(p.src[p.pos:])
+
+		if s == "" {
+			return nil, p.errorf("invalid character %q looking for beginning of value", p.peek())
+		}
+
+		p.pos += len(s)
+
+		return json.Number(s), nil
+	}
+
+	s := json5Number.FindString(p.src[p.pos:])
+
+	if s == "" {
+		return nil, p.errorf("invalid character %q looking for beginning of value", p.peek())
+	}
+
+	digits := strings.TrimLeft(s, "+-")
+
+	switch {
+	case digits == "Infinity" || digits == "NaN":
+		return nil, p.errorf("%s can't be represented in json", s)
+	case strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X"):
+		i, _ := new(big.Int).SetString(digits[2:], 16)
+
+		if s[0] == '-' {
+			i.Neg(i)
+		}
+
+		p.pos += len(s)
+
+		return json.Number(i.String()), nil
+	}
+
+	p.pos += len(s)
+
+	return yamlNumber(s), nil
+}
+
+func (p *relaxedParser) parseArray(depth int) (interface{}, error) {
+	p.pos++
+
+	array := []interface{}{}
+
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		if p.peek() == ']' {
+			p.pos++
+			return array, nil
+		}
+
+		element, err := p.parseValue(depth + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		array = append(array, element)
+
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf("expected ',' or ']' after array element")
+		}
+	}
+}
+
+func (p *relaxedParser) parseObject(depth int) (interface{}, error) {
+	p.pos++
+
+	m := make(map[string]interface{})
+
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		if p.peek() == '}' {
+			p.pos++
+			return m, nil
+		}
+
+		key, err := p.parseKey()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if m[key], err = p.parseValue(depth + 1); err != nil {
+			return nil, err
+		}
+
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, p.errorf("expected ',' or '}' after object value")
+		}
+	}
+}
+
+// Parses an object key and the colon after it.
+func (p *relaxedParser) parseKey() (string, error) {
+	var key string
+	var err error
+
+	switch c := p.peek(); {
+	case c == '"' || (c == '\'' && p.json5):
+		key, err = p.parseString()
+	case p.json5:
+		key, err = p.parseIdentifier()
+	default:
+		err = p.errorf("expected a string key")
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.skipSpace(); err != nil {
+		return "", err
+	}
+
+	if p.peek() != ':' {
+		return "", p.errorf("expected ':' after object key")
+	}
+
+	p.pos++
+
+	return key, nil
+}
+
+// Parses an unquoted JSON5 key, which follows the ECMAScript rules for identifier names.
+func (p *relaxedParser) parseIdentifier() (string, error) {
+	var b strings.Builder
+
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+
+		if r == '\\' {
+			if !strings.HasPrefix(p.src[p.pos:], "\\u") {
+				return "", p.errorf("invalid escape in key")
+			}
+
+			p.pos += 2
+
+			escaped, err := p.parseHex(4)
+
+			if err != nil {
+				return "", err
+			}
+
+			r, size = escaped, 0
+		}
+
+		isStart := r == '$' || r == '_' || unicode.IsLetter(r) || unicode.Is(unicode.Nl, r)
+		isPart := unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc) || r == '\u200c' || r == '\u200d'
+
+		if !isStart && (b.Len() == 0 || !isPart) {
+			break
+		}
+
+		b.WriteRune(r)
+		p.pos += size
+	}
+
+	if b.Len() == 0 {
+		return "", p.errorf("expected a key")
+	}
+
+	return b.String(), nil
+}
+
+// Reads n hex digits as a rune.
+func (p *relaxedParser) parseHex(n int) (rune, error) {
+	if p.pos+n > len(p.src) {
+		return 0, p.errorf("invalid escape")
+	}
+
+	r, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+
+	if err != nil {
+		return 0, p.errorf("invalid escape")
+	}
+
+	p.pos += n
+
+	return rune(r), nil
+}
+
+var relaxedEscapes = map[byte]string{
+	'"': "\"", '\\': "\\", '/': "/", 'b': "\b", 'f': "\f", 'n': "\n", 'r': "\r", 't': "\t",
+}
+
+var json5Escapes = map[byte]string{
+	'\'': "'", 'v': "\v", '0': "\x00", '\n': "", '\r': "",
+}
+
+func (p *relaxedParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+
+	for {
+		if p.pos >= len(p.src) {
+			p.pos = start
+			return "", p.errorf("unclosed string")
+		}
+
+		c := p.src[p.pos]
+
+		switch {
+		case c == quote:
+			p.pos++
+			return strings.ToValidUTF8(b.String(), "\ufffd"), nil
+		case c == '\n' || c == '\r' || (c < 0x20 && !p.json5):
+			return "", p.errorf("invalid character %q in string", c)
+		case c != '\\':
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+
+		p.pos++
+		escape := p.peek()
+		p.pos++
+
+		if s, ok := relaxedEscapes[escape]; ok {
+			b.WriteString(s)
+			continue
+		}
+
+		if s, ok := json5Escapes[escape]; ok && p.json5 {
+			if escape == '0' && p.peek() >= '0' && p.peek() <= '9' {
+				return "", p.errorf("invalid escape")
+			}
+
+			// A line continuation joins the lines.
+			if escape == '\r' && p.peek() == '\n' {
+				p.pos++
+			}
+
+			b.WriteString(s)
+			continue
+		}
+
+		switch {
+		case escape == 'u':
+			r, err := p.parseHex(4)
+
+			if err != nil {
+				return "", err
+			}
+
+			if utf16.IsSurrogate(r) && strings.HasPrefix(p.src[p.pos:], "\\u") {
+				pos := p.pos
+				p.pos += 2
+
+				low, err := p.parseHex(4)
+
+				if err != nil {
+					return "", err
+				}
+
+				if pair := utf16.DecodeRune(r, low); pair != unicode.ReplacementChar {
+					r = pair
+				} else {
+					p.pos = pos
+				}
+			}
+
+			if utf16.IsSurrogate(r) {
+				r = unicode.ReplacementChar
+			}
+
+			b.WriteRune(r)
+		case escape == 'x' && p.json5:
+			r, err := p.parseHex(2)
+
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteRune(r)
+		case p.json5 && escape != 0 && (escape < '1' || escape > '9'):
+			// Other characters, including U+2028 and U+2029 line continuations, escape to themselves.
+			p.pos--
+			r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			p.pos += size
+
+			if r != '\u2028' && r != '\u2029' {
+				b.WriteRune(r)
+			}
+		default:
+			p.pos -= 2
+			return "", p.errorf("invalid escape")
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package jason
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRelaxed(t *testing.T) {
+	tests := []struct {
+		syntax Syntax
+		input  string
+		json   string
+	}{
+		{JSONC, `{
+			// Line comment
+			"a": [1, 2,], /* block
+			comment */
+			"b": {"c": null,},
+		}`, `{"a":[1,2],"b":{"c":null}}`},
+		{JSONC, `"é😀\n"`, `"é😀\n"`},
+		{JSON5, `{
+			unquoted: 'single "quoted"',
+			$id_2: "line \
+continued",
+			'key': [0x1F, -0XfF, +1, .5, 5., 1.5e3, 1E-2],
+			esc: '\x41\v\0\q\'',
+		}`, `{"$id_2":"line continued","esc":"A\u000b\u0000q'","key":[31,-255,1,0.5,5,1.5e3,1E-2],"unquoted":"single \"quoted\""}`},
+		{JSON5, "\ufeff { a :1}\v\u00a0\u2028", `{"a":1}`},
+		{JSON5, `{café: 1, ab: 2}`, `{"ab":2,"café":1}`},
+	}
+
+	for _, test := range tests {
+		v, err := NewValueFromBytes([]byte(test.input), test.syntax)
+
+		if err != nil {
+			t.Errorf("%q: %v", test.input, err)
+			continue
+		}
+
+		if b, _ := v.Marshal(); string(b) != test.json {
+			t.Errorf("%q: expected %s, got %s", test.input, test.json, b)
+		}
+	}
+}
+
+func TestRelaxedErrors(t *testing.T) {
+	tests := []struct {
+		syntax Syntax
+		input  string
+		line   int
+	}{
+		{JSONC, `{a: 1}`, 1},
+		{JSONC, `{"a": 'b'}`, 1},
+		{JSONC, "[1,\n0x1]", 2},
+		{JSONC, "[1] [2]", 1},
+		{JSONC, "[1 /* unclosed", 1},
+		{JSONC, "[,]", 1},
+		{JSON5, "[010]", 1},
+		{JSON5, "{a: Infinity}", 1},
+		{JSON5, "[NaN]", 1},
+		{JSON5, "['\\1']", 1},
+		{JSON5, "{\n\n  1a: 2}", 3},
+		{JSON5, "'unclosed", 1},
+		{JSON5, "\"a\nb\"", 1},
+	}
+
+	for _, test := range tests {
+		_, err := NewValueFromBytes([]byte(test.input), test.syntax)
+
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: expected a SyntaxError, got %v", test.input, err)
+			continue
+		}
+
+		if syntaxErr.Line != test.line {
+			t.Errorf("%q: expected an error on line %d, got %v", test.input, test.line, err)
+		}
+	}
+}
+
+func TestRelaxedStrictDefault(t *testing.T) {
+	assert := NewAssert(t)
+
+	_, err := NewObjectFromBytes([]byte(`{"a": 1, // comment
+	}`))
+	assert.True(err != nil, "comments should be rejected by default")
+
+	o, err := NewObjectFromBytes([]byte(`{"a": 1, // comment
+	}`), JSONC)
+	assert.True(err == nil, "comments should be accepted with JSONC")
+
+	a, err := o.GetInt64("a")
+	assert.True(err == nil && a == 1, "relaxed objects should be read like strict ones")
+
+	_, err = NewObjectFromBytes([]byte(`[1]`), JSON5)
+	assert.True(err == ErrNotObject, "arrays should not be objects in relaxed syntax either")
+
+	_, err = NewValueFromBytes([]byte(`{"a": 1}`), StrictJSON)
+	assert.True(err == nil, "StrictJSON should parse json")
+}
@@ -0,0 +1,65 @@
+package schema
+
+import "testing"
+
+func TestFormats(t *testing.T) {
+	cases := []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{"date-time", []string{"2010-08-02T21:27:44Z", "2010-08-02t21:27:44.5+02:00"}, []string{"2010-08-02 21:27:44", "2010-08-02T21:27:44"}},
+		{"date", []string{"2010-08-02"}, []string{"2010-8-2", "2010-02-30"}},
+		{"time", []string{"21:27:44Z", "21:27:44.123+02:00"}, []string{"21:27", "25:00:00Z"}},
+		{"duration", []string{"P1D", "PT1H30M", "P2W", "P1Y2M3DT4H5M6S"}, []string{"P", "PT", "1D", "P1H"}},
+		{"email", []string{"anton@example.com"}, []string{"Anton <anton@example.com>", "anton", "@example.com"}},
+		{"hostname", []string{"example.com", "a-b.example"}, []string{"-a.example", "a..b", ""}},
+		{"ipv4", []string{"192.168.0.1"}, []string{"192.168.0.256", "::1", "01.2.3.4"}},
+		{"ipv6", []string{"::1", "2001:db8::1"}, []string{"192.168.0.1", "fe80::1%eth0"}},
+		{"uri", []string{"https://example.com/a?b#c", "urn:isbn:123"}, []string{"/relative", "http://exa mple.com"}},
+		{"uri-reference", []string{"/relative", "#fragment"}, []string{"a b"}},
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"123e4567e89b12d3a456426614174000"}},
+		{"regex", []string{"^a+$"}, []string{"("}},
+		{"json-pointer", []string{"", "/a/b~0c/~1"}, []string{"a", "/a~2"}},
+		{"relative-json-pointer", []string{"0", "1/a", "2#"}, []string{"/a", "01"}},
+	}
+
+	for _, c := range cases {
+		check := formats[c.format]
+
+		for _, s := range c.valid {
+			if !check(s) {
+				t.Errorf("expected %q to be a valid %s", s, c.format)
+			}
+		}
+
+		for _, s := range c.invalid {
+			if check(s) {
+				t.Errorf("expected %q to be an invalid %s", s, c.format)
+			}
+		}
+	}
+}
+
+func TestCustomFormat(t *testing.T) {
+	r := NewRegistry()
+	r.AddFormat("even", func(s string) bool { return len(s)%2 == 0 })
+	r.Add("https://example.com/s.json", mustValue(t, `{"format": "even"}`))
+
+	s, err := r.Compile("https://example.com/s.json")
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+
+	if v := violations(t, s, `"ab"`); v != nil {
+		t.Errorf("expected valid, got %v", v)
+	}
+
+	if v := violations(t, s, `"abc"`); len(v) != 1 {
+		t.Errorf("expected a format violation, got %v", v)
+	}
+
+	if v := violations(t, s, `3`); v != nil {
+		t.Errorf("format should only apply to strings, got %v", v)
+	}
+}
@@ -0,0 +1,189 @@
+package schema
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/antonholmquist/jason"
+)
+
+// Strings are inferred as an enum when they take at most this many distinct values
+// and each value occurs more than once on average.
+const maxEnumValues = 8
+
+// The dialect written to $schema by Infer.
+const draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+// Infers a schema describing all the samples.
+// Object keys that are present in every sample are required, and keys whose
+// value is null in some sample are nullable, so that a null value and a missing
+// key are told apart. Numbers are integers if Int64() accepts all of them.
+// Strings with few distinct, repeated values are described by an enum.
+// Example:
+//		s := schema.Infer(first, second, third)
+//		b, err := s.Marshal()
+func Infer(samples ...*jason.Value) *jason.Value {
+	s := &shape{}
+
+	for _, sample := range samples {
+		s.add(sample)
+	}
+
+	m := s.schema()
+	m["$schema"] = draft202012
+
+	v, _ := jason.NewValue(m)
+	return v
+}
+
+// shape accumulates what has been observed at one location across all samples.
+type shape struct {
+	present int
+	types   map[string]bool
+
+	strings     map[string]bool
+	stringCount int
+
+	objects    int
+	properties map[string]*shape
+
+	items *shape
+}
+
+func (s *shape) add(v *jason.Value) {
+	s.present++
+
+	if s.types == nil {
+		s.types = make(map[string]bool)
+	}
+
+	switch data := v.Interface().(type) {
+	case nil:
+		s.types["null"] = true
+	case bool:
+		s.types["boolean"] = true
+	case json.Number:
+		if _, err := data.Int64(); err == nil {
+			s.types["integer"] = true
+		} else {
+			s.types["number"] = true
+		}
+	case string:
+		s.types["string"] = true
+		s.stringCount++
+
+		if s.strings == nil {
+			s.strings = make(map[string]bool)
+		}
+
+		if len(s.strings) <= maxEnumValues {
+			s.strings[data] = true
+		}
+	case []interface{}:
+		s.types["array"] = true
+
+		array, _ := v.Array()
+		for _, element := range array {
+			if s.items == nil {
+				s.items = &shape{}
+			}
+			s.items.add(element)
+		}
+	case map[string]interface{}:
+		s.types["object"] = true
+		s.objects++
+
+		if s.properties == nil {
+			s.properties = make(map[string]*shape)
+		}
+
+		obj, _ := v.Object()
+		for key, child := range obj.Map() {
+			property, ok := s.properties[key]
+			if !ok {
+				property = &shape{}
+				s.properties[key] = property
+			}
+			property.add(child)
+		}
+	}
+}
+
+func (s *shape) schema() map[string]interface{} {
+	m := make(map[string]interface{})
+
+	if s.types["integer"] && s.types["number"] {
+		delete(s.types, "integer")
+	}
+
+	types := make([]string, 0, len(s.types))
+	for t := range s.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	switch len(types) {
+	case 0:
+		return m
+	case 1:
+		m["type"] = types[0]
+	default:
+		list := make([]interface{}, len(types))
+		for i, t := range types {
+			list[i] = t
+		}
+		m["type"] = list
+	}
+
+	if s.stringCount > 0 && len(s.strings) <= maxEnumValues && s.stringCount > len(s.strings) {
+		values := make([]string, 0, len(s.strings))
+		for value := range s.strings {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+
+		enum := make([]interface{}, 0, len(values)+1)
+		for _, value := range values {
+			enum = append(enum, value)
+		}
+
+		// An enum restricts all types, so the other observed types must be allowed too.
+		if len(types) == 1 || (len(types) == 2 && s.types["null"]) {
+			if s.types["null"] {
+				enum = append(enum, nil)
+			}
+			m["enum"] = enum
+		}
+	}
+
+	if s.items != nil {
+		m["items"] = s.items.schema()
+	}
+
+	if s.properties != nil {
+		properties := make(map[string]interface{})
+		var required []interface{}
+
+		keys := make([]string, 0, len(s.properties))
+		for key := range s.properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			property := s.properties[key]
+			properties[key] = property.schema()
+
+			if property.present == s.objects {
+				required = append(required, key)
+			}
+		}
+
+		m["properties"] = properties
+		if len(required) > 0 {
+			m["required"] = required
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/antonholmquist/jason"
+)
+
+func TestInfer(t *testing.T) {
+	samples := []string{
+		`{"id": 1, "kind": "user", "name": "anton", "email": null, "score": 1.5, "tags": ["a"], "address": {"city": "Stockholm"}}`,
+		`{"id": 2, "kind": "admin", "name": "walter", "email": "w@example.com", "score": 2, "tags": []}`,
+		`{"id": 3, "kind": "user", "name": "jesse", "score": 3, "tags": ["b", "c"], "address": {"city": "Albuquerque", "zip": "87101"}}`,
+		`{"id": 4, "kind": "user", "name": "skyler", "email": "s@example.com", "score": 4, "tags": ["d"]}`,
+	}
+
+	values := make([]*jason.Value, len(samples))
+	for i, s := range samples {
+		values[i] = mustValue(t, s)
+	}
+
+	inferred := Infer(values...)
+
+	expected := mustValue(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["id", "kind", "name", "score", "tags"],
+		"properties": {
+			"id": {"type": "integer"},
+			"kind": {"type": "string", "enum": ["admin", "user"]},
+			"name": {"type": "string"},
+			"email": {"type": ["null", "string"]},
+			"score": {"type": "number"},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"address": {
+				"type": "object",
+				"required": ["city"],
+				"properties": {"city": {"type": "string"}, "zip": {"type": "string"}}
+			}
+		}
+	}`)
+
+	if !inferred.Equal(expected) {
+		b, _ := inferred.Marshal()
+		t.Fatalf("unexpected schema: %s", b)
+	}
+
+	s, err := Compile(inferred)
+	if err != nil {
+		t.Fatalf("inferred schema should compile: %v", err)
+	}
+
+	for _, sample := range values {
+		if err := s.Validate(sample); err != nil {
+			t.Errorf("samples should validate against inferred schema: %v", err)
+		}
+	}
+
+	if v := violations(t, s, `{"id": 5, "kind": "root", "name": "x", "score": 1, "tags": []}`); len(v) != 1 {
+		t.Errorf("expected enum violation, got %v", v)
+	}
+}
+
+func TestInferNullableEnum(t *testing.T) {
+	inferred := Infer(mustValue(t, `["on", "off", null, "on", "off"]`))
+
+	expected := mustValue(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "array",
+		"items": {"type": ["null", "string"], "enum": ["off", "on", null]}
+	}`)
+
+	if !inferred.Equal(expected) {
+		b, _ := inferred.Marshal()
+		t.Errorf("unexpected schema: %s", b)
+	}
+}
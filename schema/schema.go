@@ -0,0 +1,987 @@
+// Package schema validates jason values against JSON Schema (draft 2020-12).
+//
+// Schemas are compiled from the same *jason.Value trees that jason parses,
+// and validation reports every violation rather than stopping at the first one.
+//
+// Compile a schema and validate a value:
+//		s, err := schema.Compile(schemaValue)
+//		err = s.Validate(value)
+//		if verr, ok := err.(*schema.ValidationError); ok {
+//			for _, violation := range verr.Violations {
+//				log.Println(violation.InstancePath, violation.Message)
+//			}
+//		}
+//
+// Schemas spread over several documents are added to a Registry under their
+// URI, so that $ref can resolve between them:
+//		r := schema.NewRegistry()
+//		r.Add("https://example.com/address.json", addressSchema)
+//		r.Add("https://example.com/person.json", personSchema)
+//		s, err := r.Compile("https://example.com/person.json")
+//
+// The keyword $dynamicRef is resolved like $ref.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antonholmquist/jason"
+)
+
+// The URI used for schemas compiled without a registry, when they lack an $id.
+const defaultURI = "urn:jason:schema"
+
+// Error values returned when compiling schemas
+var (
+	ErrInvalidSchema = errors.New("invalid schema")
+	ErrUnresolvedRef = errors.New("unresolved reference")
+)
+
+// Registry holds schema documents by URI, so that they can reference each other.
+type Registry struct {
+	documents map[string]*jason.Value
+	order     []string
+	formats   map[string]func(string) bool
+}
+
+// Creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		documents: make(map[string]*jason.Value),
+		formats:   make(map[string]func(string) bool),
+	}
+}
+
+// Adds a schema document under the given URI.
+// An $id at the root of the document takes precedence when resolving references
+// relative to it, but the document can always be compiled by the URI given here.
+func (r *Registry) Add(uri string, v *jason.Value) error {
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return err
+	}
+
+	if u.Fragment != "" {
+		return fmt.Errorf("schema: uri %q must not have a fragment", uri)
+	}
+
+	u.Fragment = ""
+	key := u.String()
+
+	if _, ok := r.documents[key]; !ok {
+		r.order = append(r.order, key)
+	}
+
+	r.documents[key] = v
+
+	return nil
+}
+
+// Registers a checker for a custom "format" value, or replaces a built-in one.
+func (r *Registry) AddFormat(name string, check func(string) bool) {
+	r.formats[name] = check
+}
+
+// Compiles the schema stored under uri, which may include a fragment
+// pointing into the document, like "https://example.com/defs.json#/$defs/address".
+func (r *Registry) Compile(uri string) (*Schema, error) {
+	c := &compiler{
+		registry: r,
+		entries:  make(map[string]entry),
+		nodes:    make(map[string]*node),
+	}
+
+	for _, key := range r.order {
+		err := c.index(r.documents[key], []location{{base: key}})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	root, err := c.resolve("", uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkCycles(root); err != nil {
+		return nil, err
+	}
+
+	return &Schema{root: root}, nil
+}
+
+// Compiles a single schema document.
+// References may only point within the document itself.
+func Compile(v *jason.Value) (*Schema, error) {
+	r := NewRegistry()
+
+	err := r.Add(defaultURI, v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Compile(defaultURI)
+}
+
+// Schema is a compiled JSON Schema, ready for validating values.
+// It is safe for concurrent use.
+type Schema struct {
+	root *node
+}
+
+// Validates v against the schema.
+// Returns nil if the value is valid, otherwise a *ValidationError holding all violations.
+func (s *Schema) Validate(v *jason.Value) error {
+	violations, _ := s.root.validate(v, "", "")
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// Violation describes one way in which a value fails to match a schema.
+type Violation struct {
+	InstancePath string // JSON Pointer to the offending part of the value
+	SchemaPath   string // JSON Pointer to the failing keyword, following $ref as a keyword
+	Message      string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s (%s)", displayPath(v.InstancePath), v.Message, displayPath(v.SchemaPath))
+}
+
+// ValidationError is returned by Validate when the value does not match the schema.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Violations) == 1 {
+		return "schema: " + e.Violations[0].String()
+	}
+
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = v.String()
+	}
+
+	return fmt.Sprintf("schema: %d violations:\n%s", len(e.Violations), strings.Join(lines, "\n"))
+}
+
+func displayPath(pointer string) string {
+	if pointer == "" {
+		return "/"
+	}
+
+	return pointer
+}
+
+// node is a compiled schema object or boolean schema.
+type node struct {
+	always *bool
+
+	ref        *node
+	refKeyword string
+
+	types      []string
+	enum       []*jason.Value
+	constValue *jason.Value
+
+	multipleOf       *big.Rat
+	maximum          *big.Rat
+	exclusiveMaximum *big.Rat
+	minimum          *big.Rat
+	exclusiveMinimum *big.Rat
+
+	maxLength   int
+	minLength   int
+	pattern     *regexp.Regexp
+	format      string
+	formatCheck func(string) bool
+
+	maxItems         int
+	minItems         int
+	uniqueItems      bool
+	prefixItems      []*node
+	items            *node
+	contains         *node
+	maxContains      int
+	minContains      int
+	unevaluatedItems *node
+
+	maxProperties         int
+	minProperties         int
+	required              []string
+	dependentRequired     map[string][]string
+	properties            map[string]*node
+	patternProperties     []patternProperty
+	additionalProperties  *node
+	propertyNames         *node
+	dependentSchemas      map[string]*node
+	unevaluatedProperties *node
+
+	allOf    []*node
+	anyOf    []*node
+	oneOf    []*node
+	not      *node
+	ifNode   *node
+	thenNode *node
+	elseNode *node
+}
+
+type patternProperty struct {
+	source string
+	re     *regexp.Regexp
+	schema *node
+}
+
+// location is a position in a schema document: the base URI of the
+// enclosing resource and a JSON Pointer from the resource root.
+type location struct {
+	base    string
+	pointer string
+}
+
+func (l location) String() string {
+	return l.base + "#" + l.pointer
+}
+
+func (l location) child(segments ...string) location {
+	pointer := l.pointer
+	for _, segment := range segments {
+		pointer += "/" + escapePointer(segment)
+	}
+
+	return location{base: l.base, pointer: pointer}
+}
+
+// entry is an indexed subschema together with its canonical location.
+type entry struct {
+	value *jason.Value
+	loc   location
+}
+
+type compiler struct {
+	registry *Registry
+	entries  map[string]entry
+	nodes    map[string]*node
+}
+
+// Keywords whose value is a single subschema.
+var schemaKeywords = []string{
+	"additionalProperties", "unevaluatedProperties", "unevaluatedItems", "items",
+	"contains", "propertyNames", "not", "if", "then", "else",
+}
+
+// Keywords whose value is an array of subschemas.
+var schemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+
+// Keywords whose value is an object of subschemas.
+var schemaMapKeywords = []string{"$defs", "definitions", "properties", "patternProperties", "dependentSchemas"}
+
+// Records every subschema of v under all the locations it can be reached by.
+// The last location is the canonical one.
+func (c *compiler) index(v *jason.Value, locs []location) error {
+	obj, err := v.Object()
+
+	if err != nil {
+		if _, err := v.Boolean(); err != nil {
+			return fmt.Errorf("schema: %s: %w: must be an object or a boolean", locs[len(locs)-1], ErrInvalidSchema)
+		}
+
+		c.register(v, locs)
+		return nil
+	}
+
+	if id, err := obj.GetString("$id"); err == nil {
+		base, err := resolveURI(locs[len(locs)-1].base, id)
+
+		if err != nil {
+			return fmt.Errorf("schema: %s: %w: bad $id %q", locs[len(locs)-1], ErrInvalidSchema, id)
+		}
+
+		base, _ = splitFragment(base)
+		if base != locs[len(locs)-1].base || locs[len(locs)-1].pointer != "" {
+			locs = append(locs[:len(locs):len(locs)], location{base: base})
+		}
+	}
+
+	c.register(v, locs)
+
+	canonical := locs[len(locs)-1]
+	for _, keyword := range []string{"$anchor", "$dynamicAnchor"} {
+		if anchor, err := obj.GetString(keyword); err == nil {
+			c.entries[canonical.base+"#"+anchor] = entry{value: v, loc: canonical}
+		}
+	}
+
+	children := func(segments ...string) []location {
+		result := make([]location, len(locs))
+		for i, loc := range locs {
+			result[i] = loc.child(segments...)
+		}
+		return result
+	}
+
+	for _, keyword := range schemaKeywords {
+		if child, err := obj.GetValue(keyword); err == nil {
+			if err := c.index(child, children(keyword)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, keyword := range schemaArrayKeywords {
+		if array, err := obj.GetValueArray(keyword); err == nil {
+			for i, child := range array {
+				if err := c.index(child, children(keyword, strconv.Itoa(i))); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, keyword := range schemaMapKeywords {
+		if m, err := obj.GetObject(keyword); err == nil {
+			for key, child := range m.Map() {
+				if err := c.index(child, children(keyword, key)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *compiler) register(v *jason.Value, locs []location) {
+	e := entry{value: v, loc: locs[len(locs)-1]}
+
+	for _, loc := range locs {
+		c.entries[loc.String()] = e
+	}
+}
+
+// Returns ErrInvalidSchema if subschemas that apply to the same value, like $ref and allOf,
+// lead back to themselves, since validating would never end.
+func (c *compiler) checkCycles(root *node) error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+
+	// Collect every subschema, then look for loops along the subschemas that apply in place.
+	var all []*node
+	seen := make(map[*node]bool)
+
+	var collect func(n *node)
+	collect = func(n *node) {
+		if seen[n] {
+			return
+		}
+
+		seen[n] = true
+		all = append(all, n)
+
+		for _, child := range append(n.inPlace(), n.children()...) {
+			collect(child)
+		}
+	}
+
+	collect(root)
+
+	state := make(map[*node]int)
+
+	var visit func(n *node) *node
+	visit = func(n *node) *node {
+		switch state[n] {
+		case visiting:
+			return n
+		case done:
+			return nil
+		}
+
+		state[n] = visiting
+
+		for _, child := range n.inPlace() {
+			if looped := visit(child); looped != nil {
+				return looped
+			}
+		}
+
+		state[n] = done
+
+		return nil
+	}
+
+	var looped *node
+	for _, n := range all {
+		if looped = visit(n); looped != nil {
+			break
+		}
+	}
+
+	if looped == nil {
+		return nil
+	}
+
+	for loc, n := range c.nodes {
+		if n == looped {
+			return fmt.Errorf("schema: %s: %w: references loop without reaching a keyword", loc, ErrInvalidSchema)
+		}
+	}
+
+	return fmt.Errorf("schema: %w: references loop without reaching a keyword", ErrInvalidSchema)
+}
+
+// Returns the subschemas that apply to the same value as n.
+func (n *node) inPlace() []*node {
+	var nodes []*node
+
+	for _, child := range []*node{n.ref, n.not, n.ifNode, n.thenNode, n.elseNode} {
+		if child != nil {
+			nodes = append(nodes, child)
+		}
+	}
+
+	nodes = append(nodes, n.allOf...)
+	nodes = append(nodes, n.anyOf...)
+	nodes = append(nodes, n.oneOf...)
+
+	for _, key := range sortedKeys(n.dependentSchemas) {
+		nodes = append(nodes, n.dependentSchemas[key])
+	}
+
+	return nodes
+}
+
+// Returns the subschemas that apply to the items, properties or property names of a value.
+func (n *node) children() []*node {
+	var nodes []*node
+
+	for _, child := range []*node{n.items, n.contains, n.unevaluatedItems, n.additionalProperties, n.propertyNames, n.unevaluatedProperties} {
+		if child != nil {
+			nodes = append(nodes, child)
+		}
+	}
+
+	nodes = append(nodes, n.prefixItems...)
+
+	for _, key := range sortedKeys(n.properties) {
+		nodes = append(nodes, n.properties[key])
+	}
+
+	for _, pp := range n.patternProperties {
+		nodes = append(nodes, pp.schema)
+	}
+
+	return nodes
+}
+
+// Resolves a reference relative to base and compiles its target.
+func (c *compiler) resolve(base, ref string) (*node, error) {
+	uri, err := resolveURI(base, ref)
+
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w %q: %v", ErrUnresolvedRef, ref, err)
+	}
+
+	document, fragment := splitFragment(uri)
+
+	if e, ok := c.entries[document+"#"+fragment]; ok {
+		return c.compile(e.value, e.loc)
+	}
+
+	// Pointers may lead into locations that are not indexed as subschemas.
+	if strings.HasPrefix(fragment, "/") {
+		if root, ok := c.entries[document+"#"]; ok {
+			target, err := followPointer(root.value, fragment)
+
+			if err == nil {
+				return c.compile(target, location{base: root.loc.base, pointer: root.loc.pointer + fragment})
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("schema: %w %q", ErrUnresolvedRef, uri)
+}
+
+// Compiles the subschema v found at loc.
+func (c *compiler) compile(v *jason.Value, loc location) (*node, error) {
+	obj, err := v.Object()
+
+	if err == nil {
+		if id, err := obj.GetString("$id"); err == nil {
+			if base, err := resolveURI(loc.base, id); err == nil {
+				base, _ = splitFragment(base)
+				loc = location{base: base}
+			}
+		}
+	}
+
+	key := loc.String()
+
+	if n, ok := c.nodes[key]; ok {
+		return n, nil
+	}
+
+	n := &node{
+		maxLength:     -1,
+		minLength:     -1,
+		maxItems:      -1,
+		minItems:      -1,
+		maxContains:   -1,
+		minContains:   -1,
+		maxProperties: -1,
+		minProperties: -1,
+	}
+	c.nodes[key] = n
+
+	if err != nil {
+		b, err := v.Boolean()
+
+		if err != nil {
+			return nil, fmt.Errorf("schema: %s: %w: must be an object or a boolean", loc, ErrInvalidSchema)
+		}
+
+		n.always = &b
+		return n, nil
+	}
+
+	p := &keywordParser{compiler: c, obj: obj, loc: loc}
+	p.parse(n)
+
+	return n, p.err
+}
+
+// keywordParser reads the keywords of one schema object into a node,
+// keeping the first error it encounters.
+type keywordParser struct {
+	compiler *compiler
+	obj      *jason.Object
+	loc      location
+	err      error
+}
+
+func (p *keywordParser) fail(keyword string, format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf("schema: %s: %w: %s", p.loc.child(keyword), ErrInvalidSchema, fmt.Sprintf(format, args...))
+	}
+}
+
+func (p *keywordParser) has(keyword string) bool {
+	_, ok := p.obj.Map()[keyword]
+	return ok
+}
+
+func (p *keywordParser) subschema(keyword string, segments ...string) *node {
+	v, err := p.obj.GetValue(append([]string{keyword}, segments...)...)
+
+	if err != nil {
+		return nil
+	}
+
+	n, err := p.compiler.compile(v, p.loc.child(append([]string{keyword}, segments...)...))
+
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+
+	return n
+}
+
+func (p *keywordParser) subschemaArray(keyword string) []*node {
+	array, err := p.obj.GetValueArray(keyword)
+
+	if err != nil {
+		if p.has(keyword) {
+			p.fail(keyword, "must be an array")
+		}
+		return nil
+	}
+
+	nodes := make([]*node, len(array))
+	for i, v := range array {
+		n, err := p.compiler.compile(v, p.loc.child(keyword, strconv.Itoa(i)))
+
+		if err != nil && p.err == nil {
+			p.err = err
+		}
+
+		nodes[i] = n
+	}
+
+	return nodes
+}
+
+func (p *keywordParser) subschemaMap(keyword string) map[string]*node {
+	m, err := p.obj.GetObject(keyword)
+
+	if err != nil {
+		if p.has(keyword) {
+			p.fail(keyword, "must be an object")
+		}
+		return nil
+	}
+
+	nodes := make(map[string]*node)
+	for key := range m.Map() {
+		nodes[key] = p.subschema(keyword, key)
+	}
+
+	return nodes
+}
+
+func (p *keywordParser) count(keyword string) int {
+	if !p.has(keyword) {
+		return -1
+	}
+
+	n, err := p.obj.GetInt64(keyword)
+
+	if err != nil || n < 0 {
+		f, ferr := p.obj.GetFloat64(keyword)
+		if ferr != nil || f < 0 || f != float64(int64(f)) {
+			p.fail(keyword, "must be a non-negative integer")
+			return -1
+		}
+		n = int64(f)
+	}
+
+	return int(n)
+}
+
+func (p *keywordParser) number(keyword string) *big.Rat {
+	if !p.has(keyword) {
+		return nil
+	}
+
+	n, err := p.obj.GetNumber(keyword)
+
+	if err != nil {
+		p.fail(keyword, "must be a number")
+		return nil
+	}
+
+	r, ok := parseRat(n)
+
+	if !ok {
+		p.fail(keyword, "is out of range")
+		return nil
+	}
+
+	return r
+}
+
+func (p *keywordParser) regexp(keyword string, source string) *regexp.Regexp {
+	re, err := regexp.Compile(source)
+
+	if err != nil {
+		p.fail(keyword, "invalid regular expression %q: %v", source, err)
+	}
+
+	return re
+}
+
+func (p *keywordParser) parse(n *node) {
+	obj := p.obj
+
+	for _, keyword := range []string{"$ref", "$dynamicRef"} {
+		if ref, err := obj.GetString(keyword); err == nil {
+			target, err := p.compiler.resolve(p.loc.base, ref)
+
+			if err != nil && p.err == nil {
+				p.err = err
+			}
+
+			n.ref = target
+			n.refKeyword = keyword
+		}
+	}
+
+	if p.has("type") {
+		if t, err := obj.GetString("type"); err == nil {
+			n.types = []string{t}
+		} else if ts, err := obj.GetStringArray("type"); err == nil {
+			n.types = ts
+		} else {
+			p.fail("type", "must be a string or an array of strings")
+		}
+
+		for _, t := range n.types {
+			switch t {
+			case "null", "boolean", "object", "array", "number", "string", "integer":
+			default:
+				p.fail("type", "unknown type %q", t)
+			}
+		}
+	}
+
+	if p.has("enum") {
+		enum, err := obj.GetValueArray("enum")
+
+		if err != nil {
+			p.fail("enum", "must be an array")
+		}
+
+		n.enum = enum
+	}
+
+	if v, err := obj.GetValue("const"); err == nil {
+		n.constValue = v
+	}
+
+	if p.has("multipleOf") {
+		num, err := obj.GetNumber("multipleOf")
+		r, ok := parseRat(num)
+
+		if err != nil || !ok || r.Sign() <= 0 {
+			p.fail("multipleOf", "must be a number greater than zero")
+		} else {
+			n.multipleOf = r
+		}
+	}
+
+	n.maximum = p.number("maximum")
+	n.exclusiveMaximum = p.number("exclusiveMaximum")
+	n.minimum = p.number("minimum")
+	n.exclusiveMinimum = p.number("exclusiveMinimum")
+
+	n.maxLength = p.count("maxLength")
+	n.minLength = p.count("minLength")
+
+	if p.has("pattern") {
+		pattern, err := obj.GetString("pattern")
+
+		if err != nil {
+			p.fail("pattern", "must be a string")
+		} else {
+			n.pattern = p.regexp("pattern", pattern)
+		}
+	}
+
+	if format, err := obj.GetString("format"); err == nil {
+		n.format = format
+		n.formatCheck = p.compiler.registry.formats[format]
+
+		if n.formatCheck == nil {
+			n.formatCheck = formats[format]
+		}
+	}
+
+	n.maxItems = p.count("maxItems")
+	n.minItems = p.count("minItems")
+
+	if p.has("uniqueItems") {
+		unique, err := obj.GetBoolean("uniqueItems")
+
+		if err != nil {
+			p.fail("uniqueItems", "must be a boolean")
+		}
+
+		n.uniqueItems = unique
+	}
+
+	n.prefixItems = p.subschemaArray("prefixItems")
+	n.items = p.subschema("items")
+	n.contains = p.subschema("contains")
+	n.maxContains = p.count("maxContains")
+	n.minContains = p.count("minContains")
+	n.unevaluatedItems = p.subschema("unevaluatedItems")
+
+	n.maxProperties = p.count("maxProperties")
+	n.minProperties = p.count("minProperties")
+
+	if p.has("required") {
+		required, err := obj.GetStringArray("required")
+
+		if err != nil {
+			p.fail("required", "must be an array of strings")
+		}
+
+		n.required = required
+	}
+
+	if p.has("dependentRequired") {
+		dependencies, err := obj.GetObject("dependentRequired")
+
+		if err != nil {
+			p.fail("dependentRequired", "must be an object")
+		} else {
+			n.dependentRequired = make(map[string][]string)
+
+			for key := range dependencies.Map() {
+				required, err := dependencies.GetStringArray(key)
+
+				if err != nil {
+					p.fail("dependentRequired", "%q must be an array of strings", key)
+				}
+
+				n.dependentRequired[key] = required
+			}
+		}
+	}
+
+	n.properties = p.subschemaMap("properties")
+
+	if patterns := p.subschemaMap("patternProperties"); patterns != nil {
+		for source, schema := range patterns {
+			n.patternProperties = append(n.patternProperties, patternProperty{
+				source: source,
+				re:     p.regexp("patternProperties", source),
+				schema: schema,
+			})
+		}
+	}
+
+	n.additionalProperties = p.subschema("additionalProperties")
+	n.propertyNames = p.subschema("propertyNames")
+	n.dependentSchemas = p.subschemaMap("dependentSchemas")
+	n.unevaluatedProperties = p.subschema("unevaluatedProperties")
+
+	n.allOf = p.subschemaArray("allOf")
+	n.anyOf = p.subschemaArray("anyOf")
+	n.oneOf = p.subschemaArray("oneOf")
+	n.not = p.subschema("not")
+	n.ifNode = p.subschema("if")
+	n.thenNode = p.subschema("then")
+	n.elseNode = p.subschema("else")
+}
+
+// Resolves ref against base. An empty base leaves ref unchanged.
+func resolveURI(base, ref string) (string, error) {
+	r, err := url.Parse(ref)
+
+	if err != nil {
+		return "", err
+	}
+
+	if base == "" {
+		return r.String(), nil
+	}
+
+	b, err := url.Parse(base)
+
+	if err != nil {
+		return "", err
+	}
+
+	if b.Opaque != "" {
+		// Opaque URIs like urn:x:y only support fragment-only references.
+		if r.Scheme == "" && r.Host == "" && r.Path == "" && r.Opaque == "" {
+			b.Fragment = r.Fragment
+			b.RawFragment = r.RawFragment
+			return b.String(), nil
+		}
+
+		return r.String(), nil
+	}
+
+	return b.ResolveReference(r).String(), nil
+}
+
+// Splits an absolute uri into the document and the unescaped fragment.
+func splitFragment(uri string) (string, string) {
+	i := strings.IndexByte(uri, '#')
+
+	if i < 0 {
+		return uri, ""
+	}
+
+	fragment, err := url.PathUnescape(uri[i+1:])
+
+	if err != nil {
+		fragment = uri[i+1:]
+	}
+
+	return uri[:i], fragment
+}
+
+// Follows a JSON Pointer from v.
+func followPointer(v *jason.Value, pointer string) (*jason.Value, error) {
+	current := v
+
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		if obj, err := current.Object(); err == nil {
+			child, ok := obj.Map()[token]
+
+			if !ok {
+				return nil, jason.KeyNotFoundError{Key: token}
+			}
+
+			current = child
+			continue
+		}
+
+		array, err := current.Array()
+
+		if err != nil {
+			return nil, err
+		}
+
+		index, err := strconv.Atoi(token)
+
+		if err != nil || index < 0 || index >= len(array) {
+			return nil, fmt.Errorf("schema: index %q out of range", token)
+		}
+
+		current = array[index]
+	}
+
+	return current, nil
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// Largest decimal exponent accepted when converting numbers exactly.
+// Protects against inputs like 1e1000000000 that would need huge allocations.
+const maxExactExponent = 10000
+
+func parseRat(n json.Number) (*big.Rat, bool) {
+	s := string(n)
+
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp > maxExactExponent || exp < -maxExactExponent {
+			return nil, false
+		}
+	}
+
+	return new(big.Rat).SetString(s)
+}
+
+// Formats a number parsed by parseRat as an exact decimal.
+func formatRat(r *big.Rat) string {
+	if r.IsInt() {
+		return r.RatString()
+	}
+
+	// The denominator only has the factors 2 and 5, so some power of ten is a multiple of it.
+	places := 0
+	scale := big.NewInt(1)
+	ten := big.NewInt(10)
+
+	for new(big.Int).Rem(scale, r.Denom()).Sign() != 0 {
+		scale.Mul(scale, ten)
+		places++
+	}
+
+	return r.FloatString(places)
+}
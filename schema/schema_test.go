@@ -0,0 +1,202 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/antonholmquist/jason"
+)
+
+func mustValue(t *testing.T, s string) *jason.Value {
+	v, err := jason.NewValueFromBytes([]byte(s))
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", s, err)
+	}
+
+	return v
+}
+
+func mustCompile(t *testing.T, s string) *Schema {
+	compiled, err := Compile(mustValue(t, s))
+	if err != nil {
+		t.Fatalf("failed to compile %s: %v", s, err)
+	}
+
+	return compiled
+}
+
+// Returns the violations of validating instance, or nil if it is valid.
+func violations(t *testing.T, s *Schema, instance string) []Violation {
+	err := s.Validate(mustValue(t, instance))
+	if err == nil {
+		return nil
+	}
+
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected a *ValidationError, got %T", err)
+	}
+
+	return verr.Violations
+}
+
+func TestLocalRefs(t *testing.T) {
+	s := mustCompile(t, `{
+		"$defs": {
+			"positive": {"type": "integer", "exclusiveMinimum": 0},
+			"node": {
+				"$anchor": "node",
+				"type": "object",
+				"properties": {
+					"value": {"$ref": "#/$defs/positive"},
+					"children": {"type": "array", "items": {"$ref": "#node"}}
+				}
+			}
+		},
+		"$ref": "#/$defs/node"
+	}`)
+
+	if v := violations(t, s, `{"value": 1, "children": [{"value": 2, "children": []}]}`); v != nil {
+		t.Errorf("expected valid tree, got %v", v)
+	}
+
+	v := violations(t, s, `{"value": 1, "children": [{"value": 0}]}`)
+	if len(v) != 1 {
+		t.Fatalf("expected one violation, got %v", v)
+	}
+
+	if v[0].InstancePath != "/children/0/value" {
+		t.Errorf("unexpected instance path %q", v[0].InstancePath)
+	}
+
+	expected := "/$ref/properties/children/items/$ref/properties/value/$ref/exclusiveMinimum"
+	if v[0].SchemaPath != expected {
+		t.Errorf("unexpected schema path %q, expected %q", v[0].SchemaPath, expected)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	r.Add("https://example.com/address.json", mustValue(t, `{
+		"type": "object",
+		"required": ["street"],
+		"properties": {"street": {"type": "string"}}
+	}`))
+
+	r.Add("https://example.com/schemas/person.json", mustValue(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"address": {"$ref": "../address.json"},
+			"tags": {"$ref": "defs.json#/$defs/tags"}
+		}
+	}`))
+
+	r.Add("https://example.com/schemas/defs.json", mustValue(t, `{
+		"$defs": {"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}}
+	}`))
+
+	s, err := r.Compile("https://example.com/schemas/person.json")
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+
+	if v := violations(t, s, `{"name": "anton", "address": {"street": "Street 42"}, "tags": ["a", "b"]}`); v != nil {
+		t.Errorf("expected valid person, got %v", v)
+	}
+
+	v := violations(t, s, `{"name": 1, "address": {}, "tags": ["a", "a"]}`)
+	if len(v) != 3 {
+		t.Errorf("expected three violations, got %v", v)
+	}
+
+	defs, err := r.Compile("https://example.com/schemas/defs.json#/$defs/tags")
+	if err != nil {
+		t.Fatalf("failed to compile fragment: %v", err)
+	}
+
+	if v := violations(t, defs, `["x", 1]`); len(v) != 1 || v[0].InstancePath != "/1" {
+		t.Errorf("unexpected violations for fragment schema: %v", v)
+	}
+}
+
+func TestEmbeddedID(t *testing.T) {
+	s := mustCompile(t, `{
+		"$id": "https://example.com/root.json",
+		"properties": {"item": {"$ref": "item.json"}},
+		"$defs": {
+			"item": {"$id": "item.json", "type": "string", "$defs": {"x": {"const": 1}}},
+			"other": {"$ref": "item.json#/$defs/x"}
+		}
+	}`)
+
+	if v := violations(t, s, `{"item": "hello"}`); v != nil {
+		t.Errorf("expected valid, got %v", v)
+	}
+
+	if v := violations(t, s, `{"item": 1}`); len(v) != 1 {
+		t.Errorf("expected one violation, got %v", v)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	invalid := []string{
+		`{"$ref": "#/$defs/missing"}`,
+		`{"$ref": "other.json"}`,
+		`{"type": "text"}`,
+		`{"minLength": -1}`,
+		`{"pattern": "("}`,
+		`{"properties": {"a": 1}}`,
+		`{"multipleOf": 0}`,
+		`[]`,
+	}
+
+	for _, s := range invalid {
+		_, err := Compile(mustValue(t, s))
+		if err == nil {
+			t.Errorf("expected compile error for %s", s)
+			continue
+		}
+
+		if !errors.Is(err, ErrInvalidSchema) && !errors.Is(err, ErrUnresolvedRef) {
+			t.Errorf("unexpected error type for %s: %v", s, err)
+		}
+	}
+}
+
+func TestRefCycles(t *testing.T) {
+	cycles := []string{
+		`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#/$defs/a"}}}`,
+		`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}}`,
+		`{"allOf": [{"$ref": "#"}]}`,
+		`{"properties": {"a": {"anyOf": [{"not": {"$ref": "#/properties/a"}}]}}}`,
+		`{"if": true, "then": {"$ref": "#"}}`,
+	}
+
+	for _, s := range cycles {
+		if _, err := Compile(mustValue(t, s)); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("%s: expected ErrInvalidSchema, got %v", s, err)
+		}
+	}
+
+	// Recursion through items and properties consumes the instance, so it ends.
+	recursive := []string{
+		`{"type": "array", "items": {"$ref": "#"}}`,
+		`{"$ref": "#/$defs/a", "$defs": {"a": {"properties": {"next": {"$ref": "#/$defs/a"}}}}}`,
+		`{"propertyNames": {"$ref": "#"}}`,
+	}
+
+	for _, s := range recursive {
+		mustCompile(t, s)
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	s := mustCompile(t, `{"required": ["name"]}`)
+
+	err := s.Validate(mustValue(t, `{}`))
+	if err == nil || err.Error() != `schema: /: missing required property "name" (/required)` {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
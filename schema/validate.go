@@ -0,0 +1,527 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antonholmquist/jason"
+)
+
+// evaluated records which properties and items of an instance were evaluated
+// by a schema and its successful subschemas. It drives unevaluatedProperties
+// and unevaluatedItems.
+type evaluated struct {
+	properties map[string]bool
+	items      int
+	allItems   bool
+	indexes    map[int]bool
+}
+
+func (e *evaluated) merge(other evaluated) {
+	for key := range other.properties {
+		e.addProperty(key)
+	}
+
+	for index := range other.indexes {
+		e.addIndex(index)
+	}
+
+	if other.items > e.items {
+		e.items = other.items
+	}
+
+	e.allItems = e.allItems || other.allItems
+}
+
+func (e *evaluated) addProperty(key string) {
+	if e.properties == nil {
+		e.properties = make(map[string]bool)
+	}
+
+	e.properties[key] = true
+}
+
+func (e *evaluated) addIndex(index int) {
+	if e.indexes == nil {
+		e.indexes = make(map[int]bool)
+	}
+
+	e.indexes[index] = true
+}
+
+func (e *evaluated) hasItem(index int) bool {
+	return e.allItems || index < e.items || e.indexes[index]
+}
+
+// validation collects the violations of one schema object applied to one value.
+type validation struct {
+	violations []Violation
+	evaluated  evaluated
+	ipath      string
+	spath      string
+}
+
+func (s *validation) fail(keyword string, format string, args ...interface{}) {
+	s.violations = append(s.violations, Violation{
+		InstancePath: s.ipath,
+		SchemaPath:   s.spath + "/" + escapePointer(keyword),
+		Message:      fmt.Sprintf(format, args...),
+	})
+}
+
+// Applies a subschema and keeps its violations. Annotations are only kept if it passes.
+func (s *validation) apply(n *node, v *jason.Value, ipath string, spath string) bool {
+	violations, evaluated := n.validate(v, ipath, spath)
+
+	if len(violations) > 0 {
+		s.violations = append(s.violations, violations...)
+		return false
+	}
+
+	s.evaluated.merge(evaluated)
+	return true
+}
+
+// Applies a subschema without keeping violations, reporting whether it passed.
+func (s *validation) test(n *node, v *jason.Value, spath string) bool {
+	violations, evaluated := n.validate(v, s.ipath, spath)
+
+	if len(violations) > 0 {
+		return false
+	}
+
+	s.evaluated.merge(evaluated)
+	return true
+}
+
+func (n *node) validate(v *jason.Value, ipath string, spath string) ([]Violation, evaluated) {
+	s := &validation{ipath: ipath, spath: spath}
+
+	if n.always != nil {
+		if !*n.always {
+			s.violations = append(s.violations, Violation{
+				InstancePath: ipath,
+				SchemaPath:   spath,
+				Message:      "no value is allowed here",
+			})
+		}
+
+		return s.violations, s.evaluated
+	}
+
+	if n.ref != nil {
+		s.apply(n.ref, v, ipath, spath+"/"+n.refKeyword)
+	}
+
+	if len(n.types) > 0 && !matchesType(v, n.types) {
+		s.fail("type", "expected %s, got %s", strings.Join(n.types, " or "), typeName(v))
+	}
+
+	if n.enum != nil {
+		found := false
+		for _, allowed := range n.enum {
+			if allowed.Equal(v) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			s.fail("enum", "value is not one of the allowed values")
+		}
+	}
+
+	if n.constValue != nil && !n.constValue.Equal(v) {
+		s.fail("const", "value does not equal the constant %s", abbreviate(n.constValue))
+	}
+
+	switch data := v.Interface().(type) {
+	case json.Number:
+		n.validateNumber(s, data)
+	case string:
+		n.validateString(s, data)
+	case []interface{}:
+		n.validateArray(s, v)
+	case map[string]interface{}:
+		n.validateObject(s, v)
+	}
+
+	n.validateCombinators(s, v)
+
+	// Unevaluated keywords depend on the annotations of all other keywords, so they come last.
+	n.validateUnevaluated(s, v)
+
+	return s.violations, s.evaluated
+}
+
+func (n *node) validateNumber(s *validation, number json.Number) {
+	if n.multipleOf == nil && n.maximum == nil && n.exclusiveMaximum == nil && n.minimum == nil && n.exclusiveMinimum == nil {
+		return
+	}
+
+	// Numbers are compared exactly, since rounding could make 1e-1000000000 pass as 0.
+	r, ok := parseRat(number)
+
+	if !ok {
+		s.fail("type", "number %s is out of range", number)
+		return
+	}
+
+	if n.multipleOf != nil && !new(big.Rat).Quo(r, n.multipleOf).IsInt() {
+		s.fail("multipleOf", "%s is not a multiple of %s", number, formatRat(n.multipleOf))
+	}
+
+	if n.maximum != nil && r.Cmp(n.maximum) > 0 {
+		s.fail("maximum", "%s is greater than the maximum %s", number, formatRat(n.maximum))
+	}
+
+	if n.exclusiveMaximum != nil && r.Cmp(n.exclusiveMaximum) >= 0 {
+		s.fail("exclusiveMaximum", "%s is not less than %s", number, formatRat(n.exclusiveMaximum))
+	}
+
+	if n.minimum != nil && r.Cmp(n.minimum) < 0 {
+		s.fail("minimum", "%s is less than the minimum %s", number, formatRat(n.minimum))
+	}
+
+	if n.exclusiveMinimum != nil && r.Cmp(n.exclusiveMinimum) <= 0 {
+		s.fail("exclusiveMinimum", "%s is not greater than %s", number, formatRat(n.exclusiveMinimum))
+	}
+}
+
+func (n *node) validateString(s *validation, str string) {
+	length := utf8.RuneCountInString(str)
+
+	if n.maxLength >= 0 && length > n.maxLength {
+		s.fail("maxLength", "string is longer than %d characters", n.maxLength)
+	}
+
+	if n.minLength >= 0 && length < n.minLength {
+		s.fail("minLength", "string is shorter than %d characters", n.minLength)
+	}
+
+	if n.pattern != nil && !n.pattern.MatchString(str) {
+		s.fail("pattern", "string does not match pattern %q", n.pattern.String())
+	}
+
+	if n.formatCheck != nil && !n.formatCheck(str) {
+		s.fail("format", "string is not a valid %s", n.format)
+	}
+}
+
+func (n *node) validateArray(s *validation, v *jason.Value) {
+	array, _ := v.Array()
+
+	if n.maxItems >= 0 && len(array) > n.maxItems {
+		s.fail("maxItems", "array has more than %d items", n.maxItems)
+	}
+
+	if n.minItems >= 0 && len(array) < n.minItems {
+		s.fail("minItems", "array has fewer than %d items", n.minItems)
+	}
+
+	if n.uniqueItems {
+		seen := make(map[[32]byte][]int)
+
+		for i, item := range array {
+			sum := item.Sum256()
+
+			for _, j := range seen[sum] {
+				if array[j].Equal(item) {
+					s.fail("uniqueItems", "items at index %d and %d are equal", j, i)
+				}
+			}
+
+			seen[sum] = append(seen[sum], i)
+		}
+	}
+
+	for i, prefix := range n.prefixItems {
+		if i >= len(array) {
+			break
+		}
+
+		s.apply(prefix, array[i], s.ipath+"/"+strconv.Itoa(i), s.spath+"/prefixItems/"+strconv.Itoa(i))
+	}
+
+	if len(n.prefixItems) > 0 {
+		s.evaluated.items = len(n.prefixItems)
+	}
+
+	if n.items != nil {
+		for i := len(n.prefixItems); i < len(array); i++ {
+			s.apply(n.items, array[i], s.ipath+"/"+strconv.Itoa(i), s.spath+"/items")
+		}
+
+		s.evaluated.allItems = true
+	}
+
+	if n.contains != nil {
+		matches := 0
+
+		for i, item := range array {
+			violations, _ := n.contains.validate(item, s.ipath+"/"+strconv.Itoa(i), s.spath+"/contains")
+
+			if len(violations) == 0 {
+				matches++
+				s.evaluated.addIndex(i)
+			}
+		}
+
+		minContains := n.minContains
+		if minContains < 0 {
+			minContains = 1
+		}
+
+		if matches < minContains {
+			s.fail("contains", "array contains %d matching items, expected at least %d", matches, minContains)
+		}
+
+		if n.maxContains >= 0 && matches > n.maxContains {
+			s.fail("maxContains", "array contains %d matching items, expected at most %d", matches, n.maxContains)
+		}
+	}
+}
+
+func (n *node) validateObject(s *validation, v *jason.Value) {
+	obj, _ := v.Object()
+	m := obj.Map()
+
+	if n.maxProperties >= 0 && len(m) > n.maxProperties {
+		s.fail("maxProperties", "object has more than %d properties", n.maxProperties)
+	}
+
+	if n.minProperties >= 0 && len(m) < n.minProperties {
+		s.fail("minProperties", "object has fewer than %d properties", n.minProperties)
+	}
+
+	for _, key := range n.required {
+		if _, ok := m[key]; !ok {
+			s.fail("required", "missing required property %q", key)
+		}
+	}
+
+	for _, key := range sortedKeys(n.dependentRequired) {
+		if _, ok := m[key]; !ok {
+			continue
+		}
+
+		for _, required := range n.dependentRequired[key] {
+			if _, ok := m[required]; !ok {
+				s.fail("dependentRequired", "property %q is required when %q is present", required, key)
+			}
+		}
+	}
+
+	keys := sortedKeys(m)
+
+	for _, key := range keys {
+		child := m[key]
+		ipath := s.ipath + "/" + escapePointer(key)
+		matched := false
+
+		if schema, ok := n.properties[key]; ok {
+			matched = true
+			s.apply(schema, child, ipath, s.spath+"/properties/"+escapePointer(key))
+			s.evaluated.addProperty(key)
+		}
+
+		for _, pattern := range n.patternProperties {
+			if pattern.re.MatchString(key) {
+				matched = true
+				s.apply(pattern.schema, child, ipath, s.spath+"/patternProperties/"+escapePointer(pattern.source))
+				s.evaluated.addProperty(key)
+			}
+		}
+
+		if !matched && n.additionalProperties != nil {
+			s.apply(n.additionalProperties, child, ipath, s.spath+"/additionalProperties")
+			s.evaluated.addProperty(key)
+		}
+
+		if n.propertyNames != nil {
+			name := stringValue(key)
+			violations, _ := n.propertyNames.validate(name, ipath, s.spath+"/propertyNames")
+
+			if len(violations) > 0 {
+				s.fail("propertyNames", "property name %q is not allowed", key)
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(n.dependentSchemas) {
+		if _, ok := m[key]; ok {
+			s.apply(n.dependentSchemas[key], v, s.ipath, s.spath+"/dependentSchemas/"+escapePointer(key))
+		}
+	}
+}
+
+func (n *node) validateCombinators(s *validation, v *jason.Value) {
+	for i, schema := range n.allOf {
+		s.apply(schema, v, s.ipath, s.spath+"/allOf/"+strconv.Itoa(i))
+	}
+
+	if len(n.anyOf) > 0 {
+		valid := 0
+
+		for i, schema := range n.anyOf {
+			if s.test(schema, v, s.spath+"/anyOf/"+strconv.Itoa(i)) {
+				valid++
+			}
+		}
+
+		if valid == 0 {
+			s.fail("anyOf", "value does not match any of the schemas")
+		}
+	}
+
+	if len(n.oneOf) > 0 {
+		var matching []string
+		var evaluated evaluated
+
+		for i, schema := range n.oneOf {
+			violations, e := schema.validate(v, s.ipath, s.spath+"/oneOf/"+strconv.Itoa(i))
+
+			if len(violations) == 0 {
+				matching = append(matching, strconv.Itoa(i))
+				evaluated = e
+			}
+		}
+
+		switch len(matching) {
+		case 0:
+			s.fail("oneOf", "value does not match any of the schemas")
+		case 1:
+			s.evaluated.merge(evaluated)
+		default:
+			s.fail("oneOf", "value matches more than one schema (%s)", strings.Join(matching, ", "))
+		}
+	}
+
+	if n.not != nil {
+		violations, _ := n.not.validate(v, s.ipath, s.spath+"/not")
+
+		if len(violations) == 0 {
+			s.fail("not", "value must not match the schema")
+		}
+	}
+
+	if n.ifNode != nil {
+		if s.test(n.ifNode, v, s.spath+"/if") {
+			if n.thenNode != nil {
+				s.apply(n.thenNode, v, s.ipath, s.spath+"/then")
+			}
+		} else if n.elseNode != nil {
+			s.apply(n.elseNode, v, s.ipath, s.spath+"/else")
+		}
+	}
+}
+
+func (n *node) validateUnevaluated(s *validation, v *jason.Value) {
+	if n.unevaluatedProperties != nil {
+		if obj, err := v.Object(); err == nil {
+			m := obj.Map()
+
+			for _, key := range sortedKeys(m) {
+				if s.evaluated.properties[key] {
+					continue
+				}
+
+				s.apply(n.unevaluatedProperties, m[key], s.ipath+"/"+escapePointer(key), s.spath+"/unevaluatedProperties")
+				s.evaluated.addProperty(key)
+			}
+		}
+	}
+
+	if n.unevaluatedItems != nil {
+		if array, err := v.Array(); err == nil {
+			for i, item := range array {
+				if s.evaluated.hasItem(i) {
+					continue
+				}
+
+				s.apply(n.unevaluatedItems, item, s.ipath+"/"+strconv.Itoa(i), s.spath+"/unevaluatedItems")
+			}
+
+			s.evaluated.allItems = true
+		}
+	}
+}
+
+// Returns the JSON type name of v.
+func typeName(v *jason.Value) string {
+	switch v.Interface().(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "unknown"
+}
+
+func matchesType(v *jason.Value, types []string) bool {
+	actual := typeName(v)
+
+	for _, t := range types {
+		if t == actual {
+			return true
+		}
+
+		if t == "integer" && actual == "number" {
+			n, _ := v.Number()
+			r, ok := parseRat(n)
+
+			if ok && r.IsInt() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns a short JSON representation of v for use in messages.
+func abbreviate(v *jason.Value) string {
+	b, err := v.Marshal()
+
+	if err != nil {
+		return "?"
+	}
+
+	if len(b) > 40 {
+		return string(b[:37]) + "..."
+	}
+
+	return string(b)
+}
+
+// Returns a string value, used to validate property names.
+func stringValue(s string) *jason.Value {
+	b, _ := json.Marshal(s)
+	v, _ := jason.NewValueFromBytes(b)
+	return v
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
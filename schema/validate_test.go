@@ -0,0 +1,112 @@
+package schema
+
+import "testing"
+
+func TestKeywords(t *testing.T) {
+	cases := []struct {
+		schema  string
+		valid   []string
+		invalid []string
+	}{
+		{`{"type": "integer"}`, []string{`1`, `1.0`, `1e3`}, []string{`1.5`, `"1"`, `null`}},
+		{`{"type": ["string", "null"]}`, []string{`"a"`, `null`}, []string{`1`, `{}`}},
+		{`{"enum": [1, "a", {"b": [true]}]}`, []string{`1.0`, `"a"`, `{"b": [true]}`}, []string{`2`, `{"b": []}`}},
+		{`{"const": {"a": 1}}`, []string{`{"a": 1e0}`}, []string{`{"a": 1, "b": 2}`}},
+		{`{"multipleOf": 0.1}`, []string{`0.3`, `10`, `-1.2`}, []string{`0.35`}},
+		{`{"minimum": 1, "exclusiveMaximum": 3}`, []string{`1`, `2.999`}, []string{`0.5`, `3`}},
+		{`{"maximum": 123456789012345678901234567890}`, []string{`123456789012345678901234567890`}, []string{`123456789012345678901234567891`}},
+		{`{"type": "integer"}`, []string{`1e100`}, []string{`1e-1000000000`, `1.00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001`}},
+		{`{"multipleOf": 0.5}`, []string{`1.5`}, []string{`1e-1000000000`, `1e1000000000`}},
+		{`{"maximum": 0}`, []string{`-1e-100`}, []string{`1e-1000000000`, `1e-100`}},
+		{`{"minimum": 0.3}`, []string{`0.3`}, []string{`0.29999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999`}},
+		{`{"minLength": 2, "maxLength": 3, "pattern": "^a"}`, []string{`"ab"`, `"aéé"`}, []string{`"a"`, `"abcd"`, `"ba"`}},
+		{`{"minItems": 1, "maxItems": 2, "uniqueItems": true}`, []string{`[1]`, `[1, "1"]`}, []string{`[]`, `[1, 2, 3]`, `[1, 1.0]`}},
+		{`{"prefixItems": [{"type": "string"}], "items": {"type": "number"}}`, []string{`["a", 1, 2]`, `[]`}, []string{`[1]`, `["a", "b"]`}},
+		{`{"contains": {"type": "string"}, "minContains": 2, "maxContains": 3}`, []string{`["a", "b", 1]`}, []string{`["a", 1]`, `["a", "b", "c", "d"]`}},
+		{`{"contains": {"type": "string"}, "minContains": 0}`, []string{`[]`, `[1]`}, nil},
+		{`{"required": ["a"], "minProperties": 1, "maxProperties": 2}`, []string{`{"a": 1}`}, []string{`{}`, `{"b": 1}`, `{"a": 1, "b": 2, "c": 3}`}},
+		{`{"dependentRequired": {"card": ["billing"]}}`, []string{`{}`, `{"card": 1, "billing": 2}`}, []string{`{"card": 1}`}},
+		{`{"dependentSchemas": {"card": {"required": ["billing"]}}}`, []string{`{"billing": 1}`}, []string{`{"card": 1}`}},
+		{`{"properties": {"a": {"type": "string"}}, "patternProperties": {"^x-": {"type": "number"}}, "additionalProperties": false}`,
+			[]string{`{"a": "s", "x-b": 1}`}, []string{`{"a": 1}`, `{"x-b": "s"}`, `{"c": 1}`}},
+		{`{"propertyNames": {"maxLength": 3}}`, []string{`{"abc": 1}`}, []string{`{"abcd": 1}`}},
+		{`{"allOf": [{"minimum": 1}, {"maximum": 3}]}`, []string{`2`}, []string{`0`, `4`}},
+		{`{"anyOf": [{"type": "string"}, {"minimum": 10}]}`, []string{`"a"`, `10`}, []string{`9`}},
+		{`{"oneOf": [{"type": "integer"}, {"minimum": 10}]}`, []string{`1`, `10.5`}, []string{`10`, `1.5`}},
+		{`{"not": {"type": "null"}}`, []string{`1`}, []string{`null`}},
+		{`{"if": {"properties": {"kind": {"const": "a"}}}, "then": {"required": ["a"]}, "else": {"required": ["b"]}}`,
+			[]string{`{"kind": "a", "a": 1}`, `{"kind": "x", "b": 1}`}, []string{`{"kind": "a", "b": 1}`, `{"kind": "x"}`}},
+		{`{"properties": {"a": true}, "allOf": [{"properties": {"b": true}}], "unevaluatedProperties": false}`,
+			[]string{`{"a": 1, "b": 2}`}, []string{`{"a": 1, "c": 3}`}},
+		{`{"anyOf": [{"properties": {"a": true}, "required": ["a"]}, {"properties": {"b": true}, "required": ["b"]}], "unevaluatedProperties": false}`,
+			[]string{`{"a": 1}`, `{"a": 1, "b": 1}`}, []string{`{"a": 1, "c": 1}`}},
+		{`{"prefixItems": [true], "contains": {"type": "string"}, "unevaluatedItems": {"type": "number"}}`,
+			[]string{`[null, "a", 1]`}, []string{`[null, "a", null]`}},
+		{`false`, nil, []string{`1`, `null`}},
+		{`true`, []string{`1`, `null`}, nil},
+	}
+
+	for _, c := range cases {
+		s := mustCompile(t, c.schema)
+
+		for _, instance := range c.valid {
+			if v := violations(t, s, instance); v != nil {
+				t.Errorf("%s: expected %s to be valid, got %v", c.schema, instance, v)
+			}
+		}
+
+		for _, instance := range c.invalid {
+			if v := violations(t, s, instance); v == nil {
+				t.Errorf("%s: expected %s to be invalid", c.schema, instance)
+			}
+		}
+	}
+}
+
+func TestAllViolations(t *testing.T) {
+	s := mustCompile(t, `{
+		"type": "object",
+		"required": ["id", "name"],
+		"properties": {
+			"id": {"type": "integer"},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		}
+	}`)
+
+	v := violations(t, s, `{"id": "x", "tags": ["a", 1, 2]}`)
+
+	expected := []Violation{
+		{InstancePath: "", SchemaPath: "/required", Message: `missing required property "name"`},
+		{InstancePath: "/id", SchemaPath: "/properties/id/type", Message: "expected integer, got string"},
+		{InstancePath: "/tags/1", SchemaPath: "/properties/tags/items/type", Message: "expected string, got number"},
+		{InstancePath: "/tags/2", SchemaPath: "/properties/tags/items/type", Message: "expected string, got number"},
+	}
+
+	if len(v) != len(expected) {
+		t.Fatalf("expected %d violations, got %v", len(expected), v)
+	}
+
+	for i := range expected {
+		if v[i] != expected[i] {
+			t.Errorf("violation %d: got %+v, expected %+v", i, v[i], expected[i])
+		}
+	}
+}
+
+func TestNumberMessages(t *testing.T) {
+	s := mustCompile(t, `{"multipleOf": 0.25, "maximum": 1.5e-3}`)
+
+	v := violations(t, s, `0.1`)
+
+	expected := []string{"0.1 is not a multiple of 0.25", "0.1 is greater than the maximum 0.0015"}
+
+	if len(v) != len(expected) {
+		t.Fatalf("expected %d violations, got %v", len(expected), v)
+	}
+
+	for i := range expected {
+		if v[i].Message != expected[i] {
+			t.Errorf("violation %d: got %q, expected %q", i, v[i].Message, expected[i])
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package jason
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Implements sql.Scanner, so JSON columns like Postgres jsonb or MySQL JSON can be scanned into values.
+// SQL NULL is scanned as a value that doesn't exist, so Null() returns ErrNotNull,
+// while a JSON null in the column is scanned as an existing null.
+// Example:
+//		var settings jason.Value
+//		err := db.QueryRow("SELECT settings FROM users WHERE id = $1", id).Scan(&settings)
+func (v *Value) Scan(src interface{}) error {
+	var parsed *Value
+	var err error
+
+	switch src := src.(type) {
+	case nil:
+		*v = Value{}
+		return nil
+	case []byte:
+		parsed, err = NewValueFromBytes(src)
+	case string:
+		parsed, err = NewValueFromBytes([]byte(src))
+	default:
+		return fmt.Errorf("jason: can't scan %T into a value", src)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	*v = Value{parsed.data, true}
+
+	return nil
+}
+
+// Implements driver.Valuer, so values can be written to JSON columns.
+// Values that don't exist, like those scanned from SQL NULL, are written as NULL.
+// Example:
+//		_, err := db.Exec("UPDATE users SET settings = $1 WHERE id = $2", v, id)
+func (v *Value) Value() (driver.Value, error) {
+	if v == nil || (!v.exists && v.data == nil) {
+		return nil, nil
+	}
+
+	return v.Marshal()
+}
+
+// Implements sql.Scanner for JSON columns holding objects.
+// SQL NULL is scanned as an object that doesn't exist.
+// Returns ErrNotObject if the column holds other JSON.
+// Example:
+//		var settings jason.Object
+//		err := row.Scan(&settings)
+func (v *Object) Scan(src interface{}) error {
+	var value Value
+
+	if err := value.Scan(src); err != nil {
+		return err
+	}
+
+	if src == nil {
+		*v = Object{}
+		return nil
+	}
+
+	o, err := value.Object()
+
+	if err != nil {
+		return err
+	}
+
+	o.exists = true
+	*v = *o
+
+	return nil
+}
+
+// Returns a driver.Valuer that writes the object to a JSON column.
+// Objects can't implement driver.Valuer themselves, since their embedded Value field
+// hides the Value method. Objects that don't exist, like those scanned from SQL NULL, are written as NULL.
+// Example:
+//		_, err := db.Exec("UPDATE users SET settings = $1 WHERE id = $2", settings.JSONValue(), id)
+func (v *Object) JSONValue() driver.Valuer {
+	if v == nil {
+		return (*Value)(nil)
+	}
+
+	return &v.Value
+}
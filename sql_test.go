@@ -0,0 +1,174 @@
+package jason
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+)
+
+var (
+	_ sql.Scanner   = &Value{}
+	_ sql.Scanner   = &Object{}
+	_ driver.Valuer = &Value{}
+)
+
+// columnDriver is a database/sql driver holding a single column.
+// Exec stores its first argument in the column and Query returns it.
+type columnDriver struct {
+	column driver.Value
+}
+
+func (d *columnDriver) Open(name string) (driver.Conn, error)     { return d, nil }
+func (d *columnDriver) Prepare(query string) (driver.Stmt, error) { return d, nil }
+func (d *columnDriver) Close() error                              { return nil }
+func (d *columnDriver) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+func (d *columnDriver) NumInput() int                             { return -1 }
+
+func (d *columnDriver) Exec(args []driver.Value) (driver.Result, error) {
+	d.column = args[0]
+	return driver.RowsAffected(1), nil
+}
+
+func (d *columnDriver) Query(args []driver.Value) (driver.Rows, error) {
+	return &columnRows{column: d.column}, nil
+}
+
+type columnRows struct {
+	column driver.Value
+	done   bool
+}
+
+func (r *columnRows) Columns() []string { return []string{"settings"} }
+func (r *columnRows) Close() error      { return nil }
+
+func (r *columnRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+
+	r.done = true
+	dest[0] = r.column
+
+	return nil
+}
+
+var testDriver = &columnDriver{}
+
+func init() {
+	sql.Register("jason-column", testDriver)
+}
+
+func TestScan(t *testing.T) {
+	assert := NewAssert(t)
+
+	var v Value
+
+	err := v.Scan([]byte(`{"name": "anton", "age": 29}`))
+	assert.True(err == nil, "scanning bytes should succeed")
+
+	o, err := v.Object()
+	name, _ := o.GetString("name")
+	assert.True(err == nil && name == "anton", "scanned bytes should be parsed")
+
+	err = v.Scan(`null`)
+	assert.True(err == nil && v.Null() == nil, "json null should be scanned as an existing null")
+
+	err = v.Scan(nil)
+	assert.True(err == nil && v.Null() == ErrNotNull, "sql NULL should be scanned as a missing value")
+
+	err = v.Scan([]byte(`{`))
+	assert.True(err != nil, "invalid json should return an error")
+
+	err = v.Scan(42)
+	assert.True(err != nil, "unsupported types should return an error")
+
+	var obj Object
+
+	err = obj.Scan(`{"id": 1}`)
+	id, _ := obj.GetInt64("id")
+	assert.True(err == nil && id == 1, "objects should be scanned")
+
+	err = obj.Scan(`[1]`)
+	assert.True(err == ErrNotObject, "arrays should not be scanned into objects")
+
+	err = obj.Scan(nil)
+	_, getErr := obj.GetInt64("id")
+	assert.True(err == nil && getErr != nil, "sql NULL should be scanned as an empty object")
+}
+
+func TestValuer(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, _ := NewObjectFromBytes([]byte(`{"b": 1, "a": [true, null]}`))
+
+	value, err := o.Value.Value()
+	b, ok := value.([]byte)
+	assert.True(err == nil && ok && string(b) == `{"a":[true,null],"b":1}`, "objects should be written as json")
+
+	var missing Value
+	value, err = missing.Value()
+	assert.True(err == nil && value == nil, "missing values should be written as NULL")
+
+	var null Value
+	null.Scan("null")
+	value, err = null.Value()
+	b, _ = value.([]byte)
+	assert.True(err == nil && string(b) == "null", "json null should be written as json")
+
+	var nilValue *Value
+	value, err = nilValue.Value()
+	assert.True(err == nil && value == nil, "nil values should be written as NULL")
+}
+
+func TestDatabaseColumn(t *testing.T) {
+	assert := NewAssert(t)
+
+	db, err := sql.Open("jason-column", "")
+	assert.True(err == nil, "failed to open database")
+	defer db.Close()
+
+	o, _ := NewObjectFromBytes([]byte(`{"theme": "dark", "fontSize": 12}`))
+
+	_, err = db.Exec("UPDATE users SET settings = ?", o.JSONValue())
+	b, _ := testDriver.column.([]byte)
+	assert.True(err == nil && string(b) == `{"fontSize":12,"theme":"dark"}`, "objects should be written through JSONValue")
+
+	var settings Object
+	err = db.QueryRow("SELECT settings FROM users").Scan(&settings)
+	theme, _ := settings.GetString("theme")
+	assert.True(err == nil && theme == "dark", "written objects should be scanned back")
+
+	var missing *Object
+	_, err = db.Exec("UPDATE users SET settings = ?", missing.JSONValue())
+	assert.True(err == nil && testDriver.column == nil, "nil objects should be written as NULL")
+
+	var v Value
+	err = db.QueryRow("SELECT settings FROM users").Scan(&v)
+	assert.True(err == nil && v.Null() == ErrNotNull, "sql NULL should be scanned as a missing value")
+
+	_, err = db.Exec("UPDATE users SET settings = ?", settings.JSONValue())
+	b, _ = testDriver.column.([]byte)
+	assert.True(err == nil && string(b) == `{"fontSize":12,"theme":"dark"}`, "scanned objects should be written back")
+}
+
+func TestNullDocument(t *testing.T) {
+	assert := NewAssert(t)
+
+	db, err := sql.Open("jason-column", "")
+	assert.True(err == nil, "failed to open database")
+	defer db.Close()
+
+	for _, option := range []ParseOption{StrictJSON, JSONC, JSON5} {
+		v, err := NewValueFromBytes([]byte(" null "), option)
+		assert.True(err == nil && v.Null() == nil, "parsed json null should be an existing null")
+
+		_, err = db.Exec("UPDATE users SET settings = ?", v)
+		b, _ := testDriver.column.([]byte)
+		assert.True(err == nil && string(b) == "null", "parsed json null should be written as json, not NULL")
+
+		var scanned Value
+		err = db.QueryRow("SELECT settings FROM users").Scan(&scanned)
+		assert.True(err == nil && scanned.Null() == nil, "json null should be scanned back as an existing null")
+	}
+}
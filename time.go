@@ -0,0 +1,255 @@
+package jason
+
+import (
+	"math/big"
+	"time"
+)
+
+// Attempts to typecast the current value into a time.Time.
+// The value must be a string in one of the given layouts, tried in order, or RFC 3339 if none are given.
+// Fractional seconds are accepted with the RFC 3339 layout.
+// Returns error if the current value is not a json string, or the error of the last layout if none match.
+// Example:
+//		createdAt, err := v.Time()
+//		birthday, err := v.Time("2006-01-02")
+func (v *Value) Time(layout ...string) (time.Time, error) {
+	s, err := v.String()
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(layout) == 0 {
+		layout = []string{time.RFC3339Nano}
+	}
+
+	for _, l := range layout {
+		var t time.Time
+		t, err = time.Parse(l, s)
+
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// Attempts to typecast the current value into a time.Duration.
+// The value must be a string accepted by time.ParseDuration, like "1h30m" or "250ms".
+// Returns error if the current value is not a json string or not a valid duration.
+// Example:
+//		timeout, err := v.Duration()
+func (v *Value) Duration() (time.Duration, error) {
+	s, err := v.String()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return time.ParseDuration(s)
+}
+
+// Attempts to typecast the current value into a time.Time, reading it as seconds since the Unix epoch.
+// Fractional seconds are kept down to the nanosecond. The time is in UTC.
+// Returns error if the current value is not a json number, or ErrOutOfRange if the time can't be represented.
+// Example:
+//		updatedAt, err := v.UnixTime()
+func (v *Value) UnixTime() (time.Time, error) {
+	return v.unixTime(int64(time.Second))
+}
+
+// Attempts to typecast the current value into a time.Time, reading it as milliseconds since the Unix epoch.
+// Returns error like UnixTime.
+// Example:
+//		updatedAt, err := v.UnixMilliTime()
+func (v *Value) UnixMilliTime() (time.Time, error) {
+	return v.unixTime(int64(time.Millisecond))
+}
+
+// Converts a number of units, each unit nanoseconds long, since the Unix epoch into a time.
+func (v *Value) unixTime(unit int64) (time.Time, error) {
+	n, err := v.Number()
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Larger exponents are out of range anyway and expensive to expand, and smaller
+	// ones are far below a nanosecond.
+	if d := parseDecimal(n); d.valid && d.digits != "" {
+		switch {
+		case d.exp > 30:
+			return time.Time{}, ErrOutOfRange
+		case d.exp < -30 && d.neg:
+			return time.Unix(0, -1).UTC(), nil
+		case d.exp < -30:
+			return time.Unix(0, 0).UTC(), nil
+		}
+	}
+
+	d, err := (&Value{n, true}).Decimal()
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	r := d.Rat()
+	r.Mul(r, new(big.Rat).SetInt64(unit))
+
+	nanos := new(big.Int).Div(r.Num(), r.Denom())
+	sec, nsec := new(big.Int).DivMod(nanos, big.NewInt(int64(time.Second)), new(big.Int))
+
+	if !sec.IsInt64() {
+		return time.Time{}, ErrOutOfRange
+	}
+
+	return time.Unix(sec.Int64(), nsec.Int64()).UTC(), nil
+}
+
+// Gets the value at key path and attempts to typecast the value into a time.Time.
+// The value must be an RFC 3339 string. Use GetValue and Time for other layouts.
+// Returns error if the value is not a json string or not a valid time.
+// Example:
+//		createdAt, err := GetTime("metadata", "created_at")
+func (v *Object) GetTime(keys ...string) (time.Time, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return child.Time()
+}
+
+// Gets the value at key path and attempts to typecast the value into a time.Duration.
+// Returns error if the value is not a json string or not a valid duration.
+// Example:
+//		timeout, err := GetDuration("server", "timeout")
+func (v *Object) GetDuration(keys ...string) (time.Duration, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return child.Duration()
+}
+
+// Gets the value at key path and attempts to typecast the value into a time.Time,
+// reading it as seconds since the Unix epoch.
+// Returns error if the value is not a json number or the time can't be represented.
+// Example:
+//		updatedAt, err := GetUnixTime("metadata", "updated_at")
+func (v *Object) GetUnixTime(keys ...string) (time.Time, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return child.UnixTime()
+}
+
+// Gets the value at key path and attempts to typecast the value into a time.Time,
+// reading it as milliseconds since the Unix epoch.
+// Returns error like GetUnixTime.
+// Example:
+//		updatedAt, err := GetUnixMilliTime("metadata", "updated_at_ms")
+func (v *Object) GetUnixMilliTime(keys ...string) (time.Time, error) {
+	child, err := v.getPath(keys)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return child.UnixMilliTime()
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of times.
+// Returns error if the value is not a json array or if any of the contained values are not RFC 3339 strings.
+// Example:
+//		logins, err := GetTimeArray("person", "logins")
+func (v *Object) GetTimeArray(keys ...string) ([]time.Time, error) {
+	array, err := v.GetValueArray(keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typedArray := make([]time.Time, len(array))
+
+	for index, arrayItem := range array {
+		if typedArray[index], err = arrayItem.Time(); err != nil {
+			return nil, err
+		}
+	}
+
+	return typedArray, nil
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of durations.
+// Returns error if the value is not a json array or if any of the contained values are not duration strings.
+// Example:
+//		backoff, err := GetDurationArray("retry", "backoff")
+func (v *Object) GetDurationArray(keys ...string) ([]time.Duration, error) {
+	array, err := v.GetValueArray(keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typedArray := make([]time.Duration, len(array))
+
+	for index, arrayItem := range array {
+		if typedArray[index], err = arrayItem.Duration(); err != nil {
+			return nil, err
+		}
+	}
+
+	return typedArray, nil
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of times,
+// reading each as seconds since the Unix epoch.
+// Returns error if the value is not a json array or if any of the contained values are not numbers.
+// Example:
+//		logins, err := GetUnixTimeArray("person", "logins")
+func (v *Object) GetUnixTimeArray(keys ...string) ([]time.Time, error) {
+	array, err := v.GetValueArray(keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typedArray := make([]time.Time, len(array))
+
+	for index, arrayItem := range array {
+		if typedArray[index], err = arrayItem.UnixTime(); err != nil {
+			return nil, err
+		}
+	}
+
+	return typedArray, nil
+}
+
+// Gets the value at key path and attempts to typecast the value into an array of times,
+// reading each as milliseconds since the Unix epoch.
+// Returns error like GetUnixTimeArray.
+func (v *Object) GetUnixMilliTimeArray(keys ...string) ([]time.Time, error) {
+	array, err := v.GetValueArray(keys...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typedArray := make([]time.Time, len(array))
+
+	for index, arrayItem := range array {
+		if typedArray[index], err = arrayItem.UnixMilliTime(); err != nil {
+			return nil, err
+		}
+	}
+
+	return typedArray, nil
+}
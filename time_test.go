@@ -0,0 +1,79 @@
+package jason
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	assert := NewAssert(t)
+
+	o, err := NewObjectFromBytes([]byte(`{
+		"created": "2024-03-01T12:30:00Z",
+		"precise": "2024-03-01T12:30:00.123456789+01:00",
+		"birthday": "1990-05-17",
+		"timeout": "1m30s",
+		"seconds": 1709296200,
+		"fractional": 1709296200.5,
+		"millis": 1709296200123,
+		"negative": -1.5,
+		"huge": 1e40,
+		"tiny": -1e-40,
+		"logins": ["2024-03-01T12:30:00Z", "2024-03-02T08:00:00Z"],
+		"backoff": ["100ms", "1s", "10s"],
+		"stamps": [0, 86400],
+		"mixed": ["2024-03-01T12:30:00Z", 1]
+	}`))
+	assert.True(err == nil, "failed to parse object")
+
+	expected := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	created, err := o.GetTime("created")
+	assert.True(err == nil && created.Equal(expected), "RFC 3339 strings should parse")
+
+	precise, err := o.GetTime("precise")
+	assert.True(err == nil && precise.Nanosecond() == 123456789, "fractional seconds should be kept")
+
+	_, err = o.GetTime("birthday")
+	assert.True(err != nil, "dates should not parse as RFC 3339")
+
+	birthdayValue, _ := o.GetValue("birthday")
+	birthday, err := birthdayValue.Time(time.RFC3339, "2006-01-02")
+	assert.True(err == nil && birthday.Year() == 1990 && birthday.Month() == time.May, "custom layouts should be tried in order")
+
+	_, err = o.GetTime("seconds")
+	assert.True(err == ErrNotString, "numbers are not RFC 3339 strings")
+
+	timeout, err := o.GetDuration("timeout")
+	assert.True(err == nil && timeout == 90*time.Second, "duration strings should parse")
+
+	seconds, err := o.GetUnixTime("seconds")
+	assert.True(err == nil && seconds.Equal(expected) && seconds.Location() == time.UTC, "unix seconds should convert to UTC times")
+
+	fractional, err := o.GetUnixTime("fractional")
+	assert.True(err == nil && fractional.Equal(expected.Add(500*time.Millisecond)), "fractional unix seconds should keep the fraction")
+
+	millis, err := o.GetUnixMilliTime("millis")
+	assert.True(err == nil && millis.Equal(expected.Add(123*time.Millisecond)), "unix milliseconds should convert")
+
+	negative, err := o.GetUnixTime("negative")
+	assert.True(err == nil && negative.Equal(time.Unix(-2, 500000000)), "negative fractional seconds should round down")
+
+	_, err = o.GetUnixTime("huge")
+	assert.True(err == ErrOutOfRange, "huge timestamps should be out of range")
+
+	tiny, err := o.GetUnixTime("tiny")
+	assert.True(err == nil && tiny.Equal(time.Unix(0, -1)), "tiny negative timestamps should round down")
+
+	logins, err := o.GetTimeArray("logins")
+	assert.True(err == nil && len(logins) == 2 && logins[0].Equal(expected), "time arrays should parse")
+
+	backoff, err := o.GetDurationArray("backoff")
+	assert.True(err == nil && len(backoff) == 3 && backoff[2] == 10*time.Second, "duration arrays should parse")
+
+	stamps, err := o.GetUnixTimeArray("stamps")
+	assert.True(err == nil && stamps[1].Equal(time.Unix(86400, 0)), "unix time arrays should convert")
+
+	_, err = o.GetTimeArray("mixed")
+	assert.True(err != nil, "arrays with invalid items should return an error")
+}
@@ -0,0 +1,115 @@
+package jason
+
+import (
+	"maps"
+	"slices"
+)
+
+// Edit tells Transform what to do with a value.
+// Create edits with Keep, Replace, Drop and Rename.
+type Edit struct {
+	drop    bool
+	replace bool
+	value   *Value
+	rename  bool
+	key     string
+}
+
+// Keeps the value as it is.
+func Keep() Edit {
+	return Edit{}
+}
+
+// Replaces the value with v. A nil v is replaced with null.
+func Replace(v *Value) Edit {
+	return Edit{replace: true, value: v}
+}
+
+// Removes the value from its parent object or array.
+func Drop() Edit {
+	return Edit{drop: true}
+}
+
+// Moves an object member to another key. Values in arrays and the root can't be renamed
+// and keep their position.
+func Rename(key string) Edit {
+	return Edit{rename: true, key: key}
+}
+
+// Returns a copy of the edit that also moves an object member to another key.
+// Example:
+//		return jason.Replace(celsius).Rename("temperature_c")
+func (e Edit) Rename(key string) Edit {
+	e.rename = true
+	e.key = key
+	return e
+}
+
+// Returns a new document where every value has been passed through fn, leaving v untouched.
+// Values are visited children before their parents, so fn sees objects and arrays with their
+// children already transformed. Paths are locations in v, before any renames or drops.
+// Object keys are visited in sorted order, and if renames make keys collide the last one wins.
+// Returns nil if fn drops the root.
+// Example:
+//		v2 := jason.Transform(v, func(path jason.Path, v *jason.Value) jason.Edit {
+//			if len(path) == 2 && path[0] == "user" && path[1] == "fullname" {
+//				return jason.Rename("name")
+//			}
+//			return jason.Keep()
+//		})
+func Transform(v *Value, fn func(path Path, v *Value) Edit) *Value {
+	data, edit := transform(Path{}, v.data, fn)
+
+	if edit.drop {
+		return nil
+	}
+
+	return &Value{data, true}
+}
+
+// Transforms the data at path, returning the new data and the edit fn chose for it.
+func transform(path Path, data interface{}, fn func(Path, *Value) Edit) (interface{}, Edit) {
+	switch d := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(d))
+
+		for _, key := range slices.Sorted(maps.Keys(d)) {
+			child, edit := transform(path.Append(key), d[key], fn)
+
+			if edit.drop {
+				continue
+			}
+
+			if edit.rename {
+				key = edit.key
+			}
+
+			m[key] = child
+		}
+
+		data = m
+	case []interface{}:
+		array := make([]interface{}, 0, len(d))
+
+		for i, element := range d {
+			child, edit := transform(path.Append(i), element, fn)
+
+			if !edit.drop {
+				array = append(array, child)
+			}
+		}
+
+		data = array
+	}
+
+	edit := fn(path, &Value{data, true})
+
+	if edit.replace {
+		data = nil
+		if edit.value != nil {
+			data = edit.value.data
+		}
+	}
+
+	return data, edit
+}
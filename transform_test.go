@@ -0,0 +1,75 @@
+package jason
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	assert := NewAssert(t)
+
+	input := `{"user": {"fullname": "anton", "password": "secret", "temp_f": 212}, "tags": ["a", "internal", "b"], "version": 1}`
+
+	v, err := NewValueFromBytes([]byte(input))
+	assert.True(err == nil, "failed to parse value")
+
+	var order []string
+
+	result := Transform(v, func(path Path, v *Value) Edit {
+		order = append(order, path.String())
+
+		if len(path) == 0 {
+			return Keep()
+		}
+
+		switch path[len(path)-1] {
+		case "fullname":
+			return Rename("name")
+		case "password":
+			return Drop()
+		case "temp_f":
+			f, _ := v.Float64()
+			celsius, _ := NewValue((f - 32) * 5 / 9)
+			return Replace(celsius).Rename("temp_c")
+		case "version":
+			two, _ := NewValue(2)
+			return Replace(two)
+		}
+
+		if s, err := v.String(); err == nil && s == "internal" {
+			return Drop()
+		}
+
+		return Keep()
+	})
+
+	b, _ := result.Marshal()
+	assert.True(string(b) == `{"tags":["a","b"],"user":{"name":"anton","temp_c":100},"version":2}`, "unexpected result "+string(b))
+
+	original, _ := v.Marshal()
+	assert.True(string(original) == compact(input), "the input should be untouched")
+
+	assert.True(order[0] == "$.tags[0]" && order[len(order)-1] == "$", "children should be visited before their parents")
+
+	dropped := Transform(v, func(path Path, v *Value) Edit {
+		return Drop()
+	})
+	assert.True(dropped == nil, "dropping the root should return nil")
+
+	renamed := Transform(v, func(path Path, v *Value) Edit {
+		if len(path) == 2 && path[0] == "tags" {
+			return Rename("x")
+		}
+		return Keep()
+	})
+	b, _ = renamed.Marshal()
+	assert.True(string(b) == compact(input), "array elements should not be renamed")
+}
+
+// Returns s in the compact form produced by Marshal.
+func compact(s string) string {
+	var data interface{}
+	json.Unmarshal([]byte(s), &data)
+	b, _ := json.Marshal(data)
+	return string(b)
+}
@@ -0,0 +1,78 @@
+package jason
+
+import (
+	"maps"
+	"slices"
+)
+
+// WalkAction tells Walk how to continue after visiting a value.
+type WalkAction int
+
+const (
+	// Continue visits the children of the value, then the rest of the document.
+	Continue WalkAction = iota
+
+	// SkipChildren skips the children of the value, but visits the rest of the document.
+	SkipChildren
+
+	// Stop ends the walk.
+	Stop
+)
+
+// Visits v and every value in it depth-first, parents before their children.
+// Object keys are visited in sorted order, and path is the location of each value relative to v.
+// The path may be retained by fn.
+// Example:
+//		jason.Walk(v, func(path jason.Path, v *jason.Value) jason.WalkAction {
+//			if len(path) > 0 && path[len(path)-1] == "password" {
+//				fmt.Println(path)
+//			}
+//			return jason.Continue
+//		})
+func Walk(v *Value, fn func(path Path, v *Value) WalkAction) {
+	walk(Path{}, v, fn, false)
+}
+
+// Visits v and every value in it depth-first like Walk, but children before their parents,
+// which suits building a new document from the bottom up. SkipChildren acts like Continue.
+// Example:
+//		jason.WalkPost(v, func(path jason.Path, v *jason.Value) jason.WalkAction {
+//			...
+//			return jason.Continue
+//		})
+func WalkPost(v *Value, fn func(path Path, v *Value) WalkAction) {
+	walk(Path{}, v, fn, true)
+}
+
+// Walks the value at path, returning false if the walk was stopped.
+func walk(path Path, v *Value, fn func(Path, *Value) WalkAction, post bool) bool {
+	if !post {
+		switch fn(path, v) {
+		case Stop:
+			return false
+		case SkipChildren:
+			return true
+		}
+	}
+
+	switch data := v.data.(type) {
+	case map[string]interface{}:
+		for _, key := range slices.Sorted(maps.Keys(data)) {
+			if !walk(path.Append(key), &Value{data[key], true}, fn, post) {
+				return false
+			}
+		}
+	case []interface{}:
+		for i, element := range data {
+			if !walk(path.Append(i), &Value{element, true}, fn, post) {
+				return false
+			}
+		}
+	}
+
+	if post {
+		return fn(path, v) != Stop
+	}
+
+	return true
+}
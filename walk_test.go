@@ -0,0 +1,68 @@
+package jason
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	assert := NewAssert(t)
+
+	v, err := NewValueFromBytes([]byte(`{"user": {"name": "anton", "password": "secret"}, "links": ["https://a.example.com", {"password": "hunter2"}], "count": 1}`))
+	assert.True(err == nil, "failed to parse value")
+
+	var visited []string
+	Walk(v, func(path Path, v *Value) WalkAction {
+		visited = append(visited, path.String())
+		return Continue
+	})
+	expected := "$ $.count $.links $.links[0] $.links[1] $.links[1].password $.user $.user.name $.user.password"
+	assert.True(strings.Join(visited, " ") == expected, "Walk should visit parents first in key order, got "+strings.Join(visited, " "))
+
+	var passwords []Path
+	Walk(v, func(path Path, v *Value) WalkAction {
+		if len(path) > 0 && path[len(path)-1] == "password" {
+			passwords = append(passwords, path)
+		}
+		return Continue
+	})
+	assert.True(len(passwords) == 2 && passwords[0].Pointer() == "/links/1/password" && passwords[1].Pointer() == "/user/password", "retained paths should stay intact")
+
+	visited = nil
+	Walk(v, func(path Path, v *Value) WalkAction {
+		visited = append(visited, path.String())
+		if path.String() == "$.links" {
+			return SkipChildren
+		}
+		return Continue
+	})
+	assert.True(strings.Join(visited, " ") == "$ $.count $.links $.user $.user.name $.user.password", "SkipChildren should skip the subtree")
+
+	visited = nil
+	Walk(v, func(path Path, v *Value) WalkAction {
+		visited = append(visited, path.String())
+		if path.String() == "$.links[0]" {
+			return Stop
+		}
+		return Continue
+	})
+	assert.True(strings.Join(visited, " ") == "$ $.count $.links $.links[0]", "Stop should end the walk")
+
+	visited = nil
+	WalkPost(v, func(path Path, v *Value) WalkAction {
+		visited = append(visited, path.String())
+		return Continue
+	})
+	expected = "$.count $.links[0] $.links[1].password $.links[1] $.links $.user.name $.user.password $.user $"
+	assert.True(strings.Join(visited, " ") == expected, "WalkPost should visit children first, got "+strings.Join(visited, " "))
+
+	visited = nil
+	WalkPost(v, func(path Path, v *Value) WalkAction {
+		visited = append(visited, path.String())
+		if path.String() == "$.links" {
+			return Stop
+		}
+		return Continue
+	})
+	assert.True(visited[len(visited)-1] == "$.links", "Stop should end the post-order walk")
+}
@@ -0,0 +1,1424 @@
+package jason
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// YAMLError is returned when a YAML document can't be parsed or represented as json.
+type YAMLError struct {
+	Line    int // 1-based line of the error
+	Column  int // 1-based column of the error
+	Message string
+}
+
+func (e *YAMLError) Error() string {
+	return fmt.Sprintf("yaml: line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// The largest number of nodes that aliases may expand to in one document,
+// which guards against documents that nest aliases to grow exponentially.
+const maxYAMLAliasNodes = 1000000
+
+// The deepest nesting of collections the YAML parser accepts, like the relaxed json parser.
+const maxYAMLDepth = maxRelaxedDepth
+
+// The tag prefix of the YAML core schema, written as !! in documents.
+const yamlTagPrefix = "tag:yaml.org,2002:"
+
+// Creates a new value from a reader holding a single YAML 1.2 document.
+// Scalars are resolved with the core schema, so true, 42 and null keep their types, and
+// aliases are expanded. Mapping keys are converted into strings.
+// Returns an error, a *YAMLError with the line if the YAML is invalid, if the stream holds more than
+// one document or if the document can't be represented as json, like .inf or complex keys.
+// Example:
+//		v, err := jason.NewValueFromYAML(file)
+func NewValueFromYAML(reader io.Reader) (*Value, error) {
+	values, err := NewValuesFromYAML(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(values) {
+	case 0:
+		return &Value{nil, true}, nil
+	case 1:
+		return values[0], nil
+	}
+
+	return nil, fmt.Errorf("yaml: stream holds %d documents, use NewValuesFromYAML", len(values))
+}
+
+// Creates values from a reader holding a stream of YAML documents separated by ---,
+// like a file of Kubernetes manifests. Documents are converted like NewValueFromYAML.
+// Example:
+//		manifests, err := jason.NewValuesFromYAML(file)
+func NewValuesFromYAML(reader io.Reader) ([]*Value, error) {
+	b, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := newYAMLParser(b)
+	documents, err := p.parseStream()
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*Value, len(documents))
+	for i, data := range documents {
+		values[i] = &Value{data, true}
+	}
+
+	return values, nil
+}
+
+type yamlAnchor struct {
+	data interface{}
+	size int
+}
+
+// yamlParser is a recursive descent parser working directly on the source text.
+type yamlParser struct {
+	src        string
+	pos        int
+	lineStarts []int
+	anchors    map[string]yamlAnchor
+	nodes      int // Nodes in the document so far, including those expanded from aliases
+	expanded   int // Nodes expanded from aliases
+}
+
+func newYAMLParser(b []byte) *yamlParser {
+	src := strings.ReplaceAll(string(b), "\r\n", "\n")
+	src = strings.TrimPrefix(src, "\ufeff")
+
+	p := &yamlParser{src: src, lineStarts: []int{0}}
+
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+
+	return p
+}
+
+func (p *yamlParser) errorAt(pos int, format string, args ...interface{}) error {
+	line := sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > pos })
+	return &YAMLError{Line: line, Column: pos - p.lineStarts[line-1] + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+// Returns the 0-based column of the current position.
+func (p *yamlParser) column() int {
+	line := sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > p.pos })
+	return p.pos - p.lineStarts[line-1]
+}
+
+func (p *yamlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+// Returns the byte n positions ahead, or 0 past the end.
+func (p *yamlParser) peekAt(n int) byte {
+	if p.pos+n < len(p.src) {
+		return p.src[p.pos+n]
+	}
+
+	return 0
+}
+
+func (p *yamlParser) peek() byte {
+	return p.peekAt(0)
+}
+
+func isYAMLBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// Reports whether c is whitespace, a line break or the end of input.
+func isYAMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == 0
+}
+
+func isFlowIndicator(c byte) bool {
+	return c == ',' || c == '[' || c == ']' || c == '{' || c == '}'
+}
+
+func (p *yamlParser) skipBlanks() {
+	for isYAMLBlank(p.peek()) {
+		p.pos++
+	}
+}
+
+// Skips blanks and a comment up to the end of the line.
+// Reports whether the rest of the line was empty.
+func (p *yamlParser) skipComment() bool {
+	p.skipBlanks()
+
+	if p.peek() == '#' {
+		for !p.eof() && p.peek() != '\n' {
+			p.pos++
+		}
+	}
+
+	return p.eof() || p.peek() == '\n'
+}
+
+// Skips empty lines and comments up to the next content.
+func (p *yamlParser) skipToContent() error {
+	for !p.skipComment() || !p.eof() {
+		if p.peek() != '\n' {
+			return nil
+		}
+
+		p.pos++
+
+		for p.peek() == ' ' {
+			p.pos++
+		}
+
+		if p.peek() == '\t' {
+			tab := p.pos
+
+			if !p.skipComment() {
+				return p.errorAt(tab, "tabs are not allowed as indentation")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Skips whitespace, line breaks and comments inside flow collections.
+func (p *yamlParser) skipFlowSpace() {
+	for {
+		switch c := p.peek(); {
+		case isYAMLBlank(c) || c == '\n':
+			p.pos++
+		case c == '#' && (p.pos == 0 || isYAMLSpace(p.src[p.pos-1])):
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Reports whether the current position starts a --- or ... line.
+func (p *yamlParser) atDocumentMarker() bool {
+	if p.column() != 0 || p.pos+3 > len(p.src) {
+		return false
+	}
+
+	marker := p.src[p.pos : p.pos+3]
+
+	return (marker == "---" || marker == "...") && isYAMLSpace(p.peekAt(3))
+}
+
+func (p *yamlParser) isSequenceEntry() bool {
+	return p.peek() == '-' && isYAMLSpace(p.peekAt(1))
+}
+
+// Parses every document of the stream.
+func (p *yamlParser) parseStream() ([]interface{}, error) {
+	documents := []interface{}{}
+
+	for {
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+
+		// Directives like %YAML 1.2 don't change how documents are read.
+		for !p.eof() && p.column() == 0 && p.peek() == '%' {
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+
+			if err := p.skipToContent(); err != nil {
+				return nil, err
+			}
+		}
+
+		if p.eof() {
+			return documents, nil
+		}
+
+		explicit := false
+
+		if p.atDocumentMarker() {
+			if p.peek() == '.' {
+				p.pos += 3
+				continue
+			}
+
+			p.pos += 3
+			explicit = true
+		}
+
+		p.anchors = make(map[string]yamlAnchor)
+		p.nodes, p.expanded = 0, 0
+
+		var data interface{}
+		var err error
+
+		if explicit {
+			data, err = p.parseInline(-1, false, 0)
+		} else {
+			data, err = p.parseBlockNode(-1, 0)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, data)
+
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+
+		if p.eof() {
+			return documents, nil
+		}
+
+		if !p.atDocumentMarker() {
+			return nil, p.errorf("expected the end of the document")
+		}
+
+		if p.peek() == '.' {
+			p.pos += 3
+
+			if !p.skipComment() {
+				return nil, p.errorf("unexpected content after the end of the document")
+			}
+		}
+	}
+}
+
+// Parses the node at the next content, if it is indented more than its parent collection.
+// Returns nil for empty nodes.
+func (p *yamlParser) parseBlockNode(parentIndent, depth int) (interface{}, error) {
+	if err := p.skipToContent(); err != nil {
+		return nil, err
+	}
+
+	if p.eof() || p.column() <= parentIndent || p.atDocumentMarker() {
+		p.nodes++
+		return nil, nil
+	}
+
+	return p.parseBlockContent(parentIndent, depth)
+}
+
+// Parses a node starting at the current position, which may be in the middle of a line
+// after "- ". The column of the position is the indentation of block collections.
+func (p *yamlParser) parseBlockContent(parentIndent, depth int) (interface{}, error) {
+	indent := p.column()
+
+	if p.isSequenceEntry() {
+		return p.parseBlockSequence(indent, depth)
+	}
+
+	if p.isImplicitKey() {
+		return p.parseBlockMapping(indent, depth)
+	}
+
+	return p.parseInline(parentIndent, false, depth)
+}
+
+// Parses the rest of a line holding a node that can't be a block collection, like the value after
+// "key:" or the content after "---". If the line is empty, the node is read from the following lines.
+// Mapping values may be block sequences at the same indentation as their key.
+func (p *yamlParser) parseInline(parentIndent int, mappingValue bool, depth int) (interface{}, error) {
+	p.skipBlanks()
+
+	nodes := p.nodes
+	start := p.pos
+
+	anchor, tag, err := p.parseProperties()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+
+	if p.skipComment() {
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+
+		if mappingValue && !p.eof() && p.column() == parentIndent && p.isSequenceEntry() {
+			data, err = p.parseBlockSequence(parentIndent, depth)
+		} else {
+			data, err = p.parseBlockNode(parentIndent, depth)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if data == nil && tag != "" {
+			p.nodes--
+			data, err = p.scalar("", tag, true, start)
+		} else {
+			err = p.checkCollectionTag(data, tag, start)
+		}
+	} else {
+		data, err = p.parseContent(parentIndent, tag, depth)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.define(anchor, data, nodes)
+
+	return data, nil
+}
+
+// Parses a node that starts on the current line after its properties.
+func (p *yamlParser) parseContent(parentIndent int, tag string, depth int) (interface{}, error) {
+	start := p.pos
+
+	var data interface{}
+	var err error
+
+	switch c := p.peek(); {
+	case c == '|' || c == '>':
+		s, err := p.parseBlockScalar(parentIndent)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// The block scalar ends at the start of the next node's line.
+		return p.scalar(s, tag, false, start)
+	case c == '[':
+		data, err = p.parseFlowSequence(depth)
+	case c == '{':
+		data, err = p.parseFlowMapping(depth)
+	case c == '"':
+		var s string
+		if s, err = p.parseDoubleQuoted(); err == nil {
+			data, err = p.scalar(s, tag, false, start)
+		}
+	case c == '\'':
+		var s string
+		if s, err = p.parseSingleQuoted(); err == nil {
+			data, err = p.scalar(s, tag, false, start)
+		}
+	case c == '*':
+		if tag != "" {
+			return nil, p.errorf("an alias can't have a tag")
+		}
+
+		data, err = p.parseAlias()
+	case p.isSequenceEntry():
+		return nil, p.errorf("block sequences are not allowed here")
+	case (c == '?' || c == ':') && isYAMLSpace(p.peekAt(1)):
+		return nil, p.errorf("explicit keys are not supported")
+	case strings.IndexByte(",]}#%@`", c) >= 0:
+		return nil, p.errorf("unexpected character %q", c)
+	default:
+		s := p.parsePlain(parentIndent, false)
+		data, err = p.scalar(s, tag, true, start)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.checkCollectionTag(data, tag, start); err != nil {
+		return nil, err
+	}
+
+	if !p.skipComment() {
+		if p.peek() == ':' {
+			return nil, p.errorf("mapping values are not allowed here")
+		}
+
+		return nil, p.errorf("unexpected %q after the value", p.peek())
+	}
+
+	return data, nil
+}
+
+// Reports whether the current position starts a mapping key followed by ": ".
+func (p *yamlParser) isImplicitKey() bool {
+	pos, nodes, expanded := p.pos, p.nodes, p.expanded
+
+	_, err := p.parseKey()
+	p.skipBlanks()
+	isKey := err == nil && p.peek() == ':' && isYAMLSpace(p.peekAt(1))
+
+	p.pos, p.nodes, p.expanded = pos, nodes, expanded
+
+	return isKey
+}
+
+// Parses a mapping key on a single line and returns it as a string.
+func (p *yamlParser) parseKey() (string, error) {
+	nodes := p.nodes
+	start := p.pos
+
+	anchor, tag, err := p.parseProperties()
+
+	if err != nil {
+		return "", err
+	}
+
+	var data interface{}
+
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		var s string
+
+		if c == '"' {
+			s, err = p.parseDoubleQuoted()
+		} else {
+			s, err = p.parseSingleQuoted()
+		}
+
+		if err == nil && strings.Contains(p.src[start:p.pos], "\n") {
+			err = p.errorAt(start, "keys must be on a single line")
+		}
+
+		if err == nil {
+			data, err = p.scalar(s, tag, false, start)
+		}
+	case c == '*':
+		data, err = p.parseAlias()
+	case c == '[' || c == '{':
+		return "", p.errorf("complex keys are not supported")
+	case c == '?' && isYAMLSpace(p.peekAt(1)):
+		return "", p.errorf("explicit keys are not supported")
+	default:
+		s := p.scanPlainLine(false)
+
+		if s == "" || (p.isSequenceEntry() && p.pos == start) {
+			return "", p.errorf("expected a mapping key")
+		}
+
+		data, err = p.scalar(s, tag, true, start)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	p.define(anchor, data, nodes)
+
+	switch data := data.(type) {
+	case string:
+		return data, nil
+	case json.Number:
+		return string(data), nil
+	case bool:
+		return strconv.FormatBool(data), nil
+	case nil:
+		return "null", nil
+	}
+
+	return "", p.errorAt(start, "keys must be scalars")
+}
+
+// Parses a block mapping whose keys are at the given indentation.
+func (p *yamlParser) parseBlockMapping(indent, depth int) (interface{}, error) {
+	if depth > maxYAMLDepth {
+		return nil, p.errorf("exceeded max depth")
+	}
+
+	m := make(map[string]interface{})
+	p.nodes++
+
+	for {
+		start := p.pos
+		key, err := p.parseKey()
+
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipBlanks()
+
+		if p.peek() != ':' || !isYAMLSpace(p.peekAt(1)) {
+			return nil, p.errorf("expected ':' after the key")
+		}
+
+		p.pos++
+
+		if _, ok := m[key]; ok {
+			return nil, p.errorAt(start, "duplicate key %q", key)
+		}
+
+		if m[key], err = p.parseInline(indent, true, depth+1); err != nil {
+			return nil, err
+		}
+
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+
+		if p.eof() || p.atDocumentMarker() || p.column() < indent {
+			return m, nil
+		}
+
+		if p.column() > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+	}
+}
+
+// Parses a block sequence whose entries are at the given indentation.
+func (p *yamlParser) parseBlockSequence(indent, depth int) (interface{}, error) {
+	if depth > maxYAMLDepth {
+		return nil, p.errorf("exceeded max depth")
+	}
+
+	array := []interface{}{}
+	p.nodes++
+
+	for {
+		p.pos++
+
+		var item interface{}
+		var err error
+
+		if p.skipComment() {
+			item, err = p.parseBlockNode(indent, depth+1)
+		} else {
+			item, err = p.parseBlockContent(indent, depth+1)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		array = append(array, item)
+
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+
+		if p.eof() || p.atDocumentMarker() || p.column() < indent || !p.isSequenceEntry() {
+			if !p.eof() && p.column() > indent {
+				return nil, p.errorf("unexpected indentation")
+			}
+
+			return array, nil
+		}
+
+		if p.column() > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+	}
+}
+
+// Parses a flow node inside a flow collection.
+func (p *yamlParser) parseFlowNode(depth int) (interface{}, error) {
+	nodes := p.nodes
+	start := p.pos
+
+	anchor, tag, err := p.parseProperties()
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipFlowSpace()
+
+	var data interface{}
+
+	switch c := p.peek(); {
+	case c == '[':
+		data, err = p.parseFlowSequence(depth)
+	case c == '{':
+		data, err = p.parseFlowMapping(depth)
+	case c == '"':
+		var s string
+		if s, err = p.parseDoubleQuoted(); err == nil {
+			data, err = p.scalar(s, tag, false, start)
+		}
+	case c == '\'':
+		var s string
+		if s, err = p.parseSingleQuoted(); err == nil {
+			data, err = p.scalar(s, tag, false, start)
+		}
+	case c == '*':
+		data, err = p.parseAlias()
+	case isFlowIndicator(c) || c == ':' || c == 0:
+		if anchor == "" && tag == "" {
+			return nil, p.errorf("expected a value")
+		}
+
+		data, err = p.scalar("", tag, true, start)
+	case strings.IndexByte("#%@`|>", c) >= 0:
+		return nil, p.errorf("unexpected character %q", c)
+	default:
+		data, err = p.scalar(p.parsePlain(-1, true), tag, true, start)
+	}
+
+	if err == nil {
+		err = p.checkCollectionTag(data, tag, start)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.define(anchor, data, nodes)
+
+	return data, nil
+}
+
+// Parses a flow sequence like [a, b].
+// Single pairs like [a: 1] are read as objects with one member.
+func (p *yamlParser) parseFlowSequence(depth int) (interface{}, error) {
+	if depth > maxYAMLDepth {
+		return nil, p.errorf("exceeded max depth")
+	}
+
+	open := p.pos
+	p.pos++
+	p.nodes++
+
+	array := []interface{}{}
+
+	for {
+		p.skipFlowSpace()
+
+		if p.eof() {
+			return nil, p.errorAt(open, "unclosed flow sequence")
+		}
+
+		if p.peek() == ']' {
+			p.pos++
+			return array, nil
+		}
+
+		start := p.pos
+		item, err := p.parseFlowNode(depth + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipFlowSpace()
+
+		if p.peek() == ':' {
+			key, ok := scalarKey(item)
+
+			if !ok {
+				return nil, p.errorAt(start, "keys must be scalars")
+			}
+
+			p.pos++
+			p.skipFlowSpace()
+
+			var value interface{}
+
+			if p.peek() == ',' || p.peek() == ']' {
+				p.nodes++
+			} else if value, err = p.parseFlowNode(depth + 1); err != nil {
+				return nil, err
+			}
+
+			item = map[string]interface{}{key: value}
+			p.nodes++
+		}
+
+		array = append(array, item)
+
+		p.skipFlowSpace()
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		case 0:
+			return nil, p.errorAt(open, "unclosed flow sequence")
+		default:
+			return nil, p.errorf("expected ',' or ']' in flow sequence")
+		}
+	}
+}
+
+// Parses a flow mapping like {a: 1, b: 2}.
+func (p *yamlParser) parseFlowMapping(depth int) (interface{}, error) {
+	if depth > maxYAMLDepth {
+		return nil, p.errorf("exceeded max depth")
+	}
+
+	open := p.pos
+	p.pos++
+	p.nodes++
+
+	m := make(map[string]interface{})
+
+	for {
+		p.skipFlowSpace()
+
+		if p.eof() {
+			return nil, p.errorAt(open, "unclosed flow mapping")
+		}
+
+		if p.peek() == '}' {
+			p.pos++
+			return m, nil
+		}
+
+		if p.peek() == '?' && isYAMLSpace(p.peekAt(1)) {
+			p.pos++
+			p.skipFlowSpace()
+		}
+
+		start := p.pos
+		keyData, err := p.parseFlowNode(depth + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := scalarKey(keyData)
+
+		if !ok {
+			return nil, p.errorAt(start, "keys must be scalars")
+		}
+
+		if _, ok := m[key]; ok {
+			return nil, p.errorAt(start, "duplicate key %q", key)
+		}
+
+		p.skipFlowSpace()
+
+		var value interface{}
+
+		if p.peek() == ':' {
+			p.pos++
+			p.skipFlowSpace()
+
+			if p.peek() == ',' || p.peek() == '}' {
+				p.nodes++
+			} else if value, err = p.parseFlowNode(depth + 1); err != nil {
+				return nil, err
+			}
+		} else {
+			p.nodes++
+		}
+
+		m[key] = value
+
+		p.skipFlowSpace()
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		case 0:
+			return nil, p.errorAt(open, "unclosed flow mapping")
+		default:
+			return nil, p.errorf("expected ',' or '}' in flow mapping")
+		}
+	}
+}
+
+// Converts scalar data into an object key.
+func scalarKey(data interface{}) (string, bool) {
+	switch data := data.(type) {
+	case string:
+		return data, true
+	case json.Number:
+		return string(data), true
+	case bool:
+		return strconv.FormatBool(data), true
+	case nil:
+		return "null", true
+	}
+
+	return "", false
+}
+
+// Parses anchors and tags before a node.
+func (p *yamlParser) parseProperties() (anchor, tag string, err error) {
+	for {
+		switch p.peek() {
+		case '&':
+			if anchor != "" {
+				return "", "", p.errorf("a node can only have one anchor")
+			}
+
+			p.pos++
+
+			if anchor = p.scanName(); anchor == "" {
+				return "", "", p.errorf("expected an anchor name")
+			}
+		case '!':
+			if tag != "" {
+				return "", "", p.errorf("a node can only have one tag")
+			}
+
+			if tag, err = p.scanTag(); err != nil {
+				return "", "", err
+			}
+		default:
+			return anchor, tag, nil
+		}
+
+		p.skipBlanks()
+	}
+}
+
+// Scans an anchor or alias name.
+func (p *yamlParser) scanName() string {
+	start := p.pos
+
+	for !isYAMLSpace(p.peek()) && !isFlowIndicator(p.peek()) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// Scans a tag, expanding !! to the core schema prefix.
+func (p *yamlParser) scanTag() (string, error) {
+	if strings.HasPrefix(p.src[p.pos:], "!<") {
+		end := strings.IndexByte(p.src[p.pos:], '>')
+
+		if end < 0 {
+			return "", p.errorf("unclosed verbatim tag")
+		}
+
+		tag := p.src[p.pos+2 : p.pos+end]
+		p.pos += end + 1
+
+		return tag, nil
+	}
+
+	tag := p.scanName()
+
+	if strings.HasPrefix(tag, "!!") {
+		return yamlTagPrefix + tag[2:], nil
+	}
+
+	return tag, nil
+}
+
+// Records an anchored node with the number of nodes it holds.
+func (p *yamlParser) define(anchor string, data interface{}, nodesBefore int) {
+	if anchor != "" {
+		p.anchors[anchor] = yamlAnchor{data, p.nodes - nodesBefore}
+	}
+}
+
+// Parses an alias, returning the data of its anchor.
+func (p *yamlParser) parseAlias() (interface{}, error) {
+	start := p.pos
+	p.pos++
+
+	name := p.scanName()
+	anchor, ok := p.anchors[name]
+
+	if !ok {
+		return nil, p.errorAt(start, "unknown anchor %q", name)
+	}
+
+	p.nodes += anchor.size
+	p.expanded += anchor.size
+
+	if p.expanded > maxYAMLAliasNodes {
+		return nil, p.errorAt(start, "aliases expand to more than %d nodes", maxYAMLAliasNodes)
+	}
+
+	return anchor.data, nil
+}
+
+// Checks that a tag on a collection matches its kind.
+func (p *yamlParser) checkCollectionTag(data interface{}, tag string, pos int) error {
+	switch data.(type) {
+	case map[string]interface{}:
+		if tag != "" && tag != yamlTagPrefix+"map" && strings.HasPrefix(tag, yamlTagPrefix) {
+			return p.errorAt(pos, "tag %s can't be applied to a mapping", tag)
+		}
+	case []interface{}:
+		if tag != "" && tag != yamlTagPrefix+"seq" && strings.HasPrefix(tag, yamlTagPrefix) {
+			return p.errorAt(pos, "tag %s can't be applied to a sequence", tag)
+		}
+	}
+
+	return nil
+}
+
+var (
+	yamlNull     = regexp.MustCompile(`^(~|null|Null|NULL|)$`)
+	yamlTrue     = regexp.MustCompile(`^(true|True|TRUE)$`)
+	yamlFalse    = regexp.MustCompile(`^(false|False|FALSE)$`)
+	yamlDecimal  = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlOctal    = regexp.MustCompile(`^0o[0-7]+$`)
+	yamlHex      = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	yamlFloat    = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+	yamlInfinity = regexp.MustCompile(`^([-+]?\.(inf|Inf|INF)|\.(nan|NaN|NAN))$`)
+)
+
+// Converts a scalar into data, resolving its type from the tag, or from the
+// core schema for untagged plain scalars.
+func (p *yamlParser) scalar(s string, tag string, plain bool, pos int) (interface{}, error) {
+	p.nodes++
+
+	switch tag {
+	case "":
+		if plain {
+			return p.resolve(s, pos)
+		}
+
+		return s, nil
+	case "!", yamlTagPrefix + "str", yamlTagPrefix + "binary", yamlTagPrefix + "timestamp":
+		return s, nil
+	case yamlTagPrefix + "null":
+		if yamlNull.MatchString(s) {
+			return nil, nil
+		}
+	case yamlTagPrefix + "bool":
+		if yamlTrue.MatchString(s) || yamlFalse.MatchString(s) {
+			return p.resolve(s, pos)
+		}
+	case yamlTagPrefix + "int":
+		if yamlDecimal.MatchString(s) || yamlOctal.MatchString(s) || yamlHex.MatchString(s) {
+			return p.resolve(s, pos)
+		}
+	case yamlTagPrefix + "float":
+		if yamlFloat.MatchString(s) || yamlInfinity.MatchString(s) {
+			return p.resolve(s, pos)
+		}
+	case yamlTagPrefix + "map", yamlTagPrefix + "seq":
+		return nil, p.errorAt(pos, "tag %s can't be applied to a scalar", tag)
+	default:
+		// Application specific tags don't change the value.
+		if plain {
+			return p.resolve(s, pos)
+		}
+
+		return s, nil
+	}
+
+	return nil, p.errorAt(pos, "%q is not a valid %s", s, strings.TrimPrefix(tag, yamlTagPrefix))
+}
+
+// Resolves a plain scalar with the core schema.
+func (p *yamlParser) resolve(s string, pos int) (interface{}, error) {
+	switch {
+	case yamlNull.MatchString(s):
+		return nil, nil
+	case yamlTrue.MatchString(s):
+		return true, nil
+	case yamlFalse.MatchString(s):
+		return false, nil
+	case yamlOctal.MatchString(s):
+		i, _ := new(big.Int).SetString(s[2:], 8)
+		return json.Number(i.String()), nil
+	case yamlHex.MatchString(s):
+		i, _ := new(big.Int).SetString(s[2:], 16)
+		return json.Number(i.String()), nil
+	case yamlDecimal.MatchString(s) || yamlFloat.MatchString(s):
+		return yamlNumber(s), nil
+	case yamlInfinity.MatchString(s):
+		return nil, p.errorAt(pos, "%s can't be represented in json", s)
+	}
+
+	return s, nil
+}
+
+// Converts a YAML number into the json number syntax.
+func yamlNumber(s string) json.Number {
+	sign := ""
+
+	if s[0] == '-' || s[0] == '+' {
+		if s[0] == '-' {
+			sign = "-"
+		}
+
+		s = s[1:]
+	}
+
+	mantissa, exponent := s, ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exponent = s[:i], s[i:]
+	}
+
+	integer, fraction := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		integer, fraction = mantissa[:i], mantissa[i+1:]
+	}
+
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+
+	n := sign + integer
+	if fraction != "" {
+		n += "." + fraction
+	}
+
+	return json.Number(n + exponent)
+}
+
+// Scans the text of a plain scalar on the current line, without trailing blanks.
+func (p *yamlParser) scanPlainLine(flow bool) string {
+	start := p.pos
+	end := p.pos
+
+	for !p.eof() {
+		c := p.peek()
+
+		if c == '\n' ||
+			(c == ':' && (isYAMLSpace(p.peekAt(1)) || (flow && isFlowIndicator(p.peekAt(1))))) ||
+			(c == '#' && p.pos > start && isYAMLBlank(p.src[p.pos-1])) ||
+			(flow && isFlowIndicator(c)) {
+			break
+		}
+
+		p.pos++
+
+		if !isYAMLBlank(c) {
+			end = p.pos
+		}
+	}
+
+	p.pos = end
+
+	return p.src[start:end]
+}
+
+// Parses a plain scalar, which continues on following lines that are indented more than
+// the parent collection. Line breaks are folded into spaces, and empty lines into line breaks.
+func (p *yamlParser) parsePlain(parentIndent int, flow bool) string {
+	var b strings.Builder
+
+	b.WriteString(p.scanPlainLine(flow))
+
+	for {
+		end := p.pos
+		p.skipBlanks()
+
+		if p.peek() != '\n' {
+			p.pos = end
+			break
+		}
+
+		breaks := 0
+		for p.peek() == '\n' {
+			p.pos++
+			breaks++
+			p.skipBlanks()
+		}
+
+		if p.eof() || p.peek() == '#' || (!flow && p.column() <= parentIndent) || p.atDocumentMarker() {
+			p.pos = end
+			break
+		}
+
+		line := p.scanPlainLine(flow)
+
+		if line == "" {
+			p.pos = end
+			break
+		}
+
+		if breaks == 1 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(strings.Repeat("\n", breaks-1))
+		}
+
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
+
+// Folds the line breaks inside a quoted scalar, starting at a line break.
+func (p *yamlParser) foldQuoted(b *strings.Builder) {
+	breaks := 0
+
+	for p.peek() == '\n' {
+		p.pos++
+		breaks++
+		p.skipBlanks()
+	}
+
+	if breaks == 1 {
+		b.WriteByte(' ')
+	} else {
+		b.WriteString(strings.Repeat("\n", breaks-1))
+	}
+}
+
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v", 'f': "\f",
+	'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// Parses a double-quoted scalar with its escapes.
+func (p *yamlParser) parseDoubleQuoted() (string, error) {
+	open := p.pos
+	p.pos++
+
+	var b strings.Builder
+
+	for {
+		if p.eof() {
+			return "", p.errorAt(open, "unclosed double-quoted string")
+		}
+
+		switch c := p.peek(); {
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		case c == '\n':
+			p.foldQuoted(&b)
+		case isYAMLBlank(c):
+			start := p.pos
+			p.skipBlanks()
+
+			if p.peek() != '\n' {
+				b.WriteString(p.src[start:p.pos])
+			}
+		case c == '\\':
+			escape := p.peekAt(1)
+			p.pos += 2
+
+			if escape == '\n' {
+				// An escaped line break joins the lines without a space.
+				p.skipBlanks()
+				continue
+			}
+
+			if s, ok := yamlEscapes[escape]; ok {
+				b.WriteString(s)
+				continue
+			}
+
+			digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[escape]
+
+			if digits == 0 || p.pos+digits > len(p.src) {
+				return "", p.errorAt(p.pos-2, "invalid escape sequence")
+			}
+
+			r, err := strconv.ParseUint(p.src[p.pos:p.pos+digits], 16, 32)
+
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return "", p.errorAt(p.pos-2, "invalid escape sequence")
+			}
+
+			b.WriteRune(rune(r))
+			p.pos += digits
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// Parses a single-quoted scalar, where '' is a quote.
+func (p *yamlParser) parseSingleQuoted() (string, error) {
+	open := p.pos
+	p.pos++
+
+	var b strings.Builder
+
+	for {
+		if p.eof() {
+			return "", p.errorAt(open, "unclosed single-quoted string")
+		}
+
+		switch c := p.peek(); {
+		case c == '\'' && p.peekAt(1) == '\'':
+			b.WriteByte('\'')
+			p.pos += 2
+		case c == '\'':
+			p.pos++
+			return b.String(), nil
+		case c == '\n':
+			p.foldQuoted(&b)
+		case isYAMLBlank(c):
+			start := p.pos
+			p.skipBlanks()
+
+			if p.peek() != '\n' {
+				b.WriteString(p.src[start:p.pos])
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// Parses a literal (|) or folded (>) block scalar, leaving the position at the start
+// of the first line after it.
+func (p *yamlParser) parseBlockScalar(parentIndent int) (string, error) {
+	literal := p.peek() == '|'
+	p.pos++
+
+	var chomping byte
+	indent := 0
+
+	for i := 0; i < 2; i++ {
+		switch c := p.peek(); {
+		case (c == '+' || c == '-') && chomping == 0:
+			chomping = c
+			p.pos++
+		case c >= '1' && c <= '9' && indent == 0:
+			indent = parentIndent + int(c-'0')
+			if indent < 0 {
+				indent = 0
+			}
+			p.pos++
+		}
+	}
+
+	if !p.skipComment() {
+		return "", p.errorf("invalid block scalar header")
+	}
+
+	if !p.eof() {
+		p.pos++
+	}
+
+	var lines []string
+	detect := indent == 0
+	maxEmpty := 0
+
+	for !p.eof() {
+		start := p.pos
+
+		spaces := 0
+		for p.peek() == ' ' {
+			p.pos++
+			spaces++
+		}
+
+		end := strings.IndexByte(p.src[p.pos:], '\n')
+		if end < 0 {
+			end = len(p.src)
+		} else {
+			end += p.pos
+		}
+
+		text := p.src[p.pos:end]
+
+		if spaces == 0 && p.atDocumentMarker() {
+			p.pos = start
+			break
+		}
+
+		if detect {
+			if text == "" {
+				maxEmpty = max(maxEmpty, spaces)
+				lines = append(lines, "")
+				p.pos = min(end+1, len(p.src))
+				continue
+			}
+
+			if spaces <= parentIndent {
+				p.pos = start
+				break
+			}
+
+			indent, detect = spaces, false
+
+			if maxEmpty > indent {
+				return "", p.errorAt(start, "empty lines are indented more than the block scalar")
+			}
+		}
+
+		switch {
+		case text == "" && spaces <= indent:
+			lines = append(lines, "")
+		case spaces < indent:
+			p.pos = start
+			return foldBlockScalar(lines, literal, chomping), nil
+		default:
+			lines = append(lines, strings.Repeat(" ", spaces-indent)+text)
+		}
+
+		p.pos = min(end+1, len(p.src))
+	}
+
+	return foldBlockScalar(lines, literal, chomping), nil
+}
+
+// Joins the lines of a block scalar and applies the chomping indicator.
+func foldBlockScalar(lines []string, literal bool, chomping byte) string {
+	last := len(lines)
+	for last > 0 && lines[last-1] == "" {
+		last--
+	}
+
+	content, trailing := lines[:last], len(lines)-last
+
+	var b strings.Builder
+
+	if literal {
+		b.WriteString(strings.Join(content, "\n"))
+	} else {
+		empty := 0
+		started, previousMore := false, false
+
+		for _, line := range content {
+			if line == "" {
+				empty++
+				continue
+			}
+
+			more := line[0] == ' ' || line[0] == '\t'
+
+			switch {
+			case !started:
+				b.WriteString(strings.Repeat("\n", empty))
+			case !previousMore && !more && empty == 0:
+				b.WriteByte(' ')
+			case !previousMore && !more:
+				b.WriteString(strings.Repeat("\n", empty))
+			default:
+				b.WriteString(strings.Repeat("\n", empty+1))
+			}
+
+			b.WriteString(line)
+			started, previousMore, empty = true, more, 0
+		}
+	}
+
+	switch chomping {
+	case '-':
+	case '+':
+		if last > 0 {
+			b.WriteByte('\n')
+		}
+
+		b.WriteString(strings.Repeat("\n", trailing))
+	default:
+		if last > 0 {
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package jason
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func yamlJSON(t *testing.T, yaml string) string {
+	assert := NewAssert(t)
+
+	v, err := NewValueFromYAML(strings.NewReader(yaml))
+	assert.True(err == nil, fmt.Sprintf("failed to parse %q: %v", yaml, err))
+
+	b, err := v.Marshal()
+	assert.True(err == nil, fmt.Sprintf("failed to marshal %q: %v", yaml, err))
+
+	return string(b)
+}
+
+func TestYAML(t *testing.T) {
+	assert := NewAssert(t)
+
+	tests := []struct {
+		yaml string
+		json string
+	}{
+		{"", `null`},
+		{"# only a comment\n", `null`},
+		{"name: jason\nstars: 42\n", `{"name":"jason","stars":42}`},
+		{"a: [1, 2, {b: c}]\nd: {e: f, g: }\n", `{"a":[1,2,{"b":"c"}],"d":{"e":"f","g":null}}`},
+		{"- a\n- - b\n  - c\n- d: 1\n  e: 2\n", `["a",["b","c"],{"d":1,"e":2}]`},
+		{"list:\n- a\n- b\nnext: x\n", `{"list":["a","b"],"next":"x"}`},
+		{"outer:\n  inner:\n    deep: 1\n  other: 2\n", `{"outer":{"inner":{"deep":1},"other":2}}`},
+		{"key: value # comment\n# line comment\nurl: http://example.com/#x\n", `{"key":"value","url":"http://example.com/#x"}`},
+		{"plain: one\n  two\n\n  three\n", `{"plain":"one two\nthree"}`},
+
+		// Core schema
+		{"[null, ~, Null, '', true, False, 12, -0o17, 0o17, 0x1F, +3, 007, 1.5, .5, 1., 1e3, -2.5E-3]",
+			`[null,null,null,"",true,false,12,"-0o17",15,31,3,7,1.5,0.5,1,1e3,-2.5E-3]`},
+		{"[yes, no, on, 1_000, 0b1, '12', \"true\"]", `["yes","no","on","1_000","0b1","12","true"]`},
+		{"[!!str 12, !!int '12', !!float \"1\", !!bool 'true', !!null '', !custom 1]", `["12",12,1,true,null,1]`},
+		{"1: a\ntrue: b\nnull: c\n", `{"1":"a","null":"c","true":"b"}`},
+
+		// Quoted scalars
+		{`"a\tb\n\"c\" \u00e9\x41\
+  d"`, `"a\tb\n\"c\" éAd"`},
+		{"'it''s'", `"it's"`},
+		{"\"folded\n  line\n\n  para\"", `"folded line\npara"`},
+
+		// Block scalars
+		{"text: |\n  line 1\n   indented\n\n  line 3\nnext: 1\n", `{"next":1,"text":"line 1\n indented\n\nline 3\n"}`},
+		{"text: >\n  folded\n  line\n\n  para\n    more\n  end\n", `{"text":"folded line\npara\n  more\nend\n"}`},
+		{"- |-\n  strip\n\n- |+\n  keep\n\n- >2\n    indented\n", `["strip","keep\n\n","  indented\n"]`},
+
+		// Anchors and aliases
+		{"base: &base {a: 1}\ncopy: *base\nlist: [&x 1, *x]\n", `{"base":{"a":1},"copy":{"a":1},"list":[1,1]}`},
+		{"- &m\n  k: v\n- *m\n", `[{"k":"v"},{"k":"v"}]`},
+
+		// Documents
+		{"%YAML 1.2\n---\na: 1\n...\n", `{"a":1}`},
+		{"--- text\n", `"text"`},
+		{"--- |\n  block\n", `"block\n"`},
+		{"---\n- a\n", `["a"]`},
+		{"\ufeffa: b\r\nc: d\r\n", `{"a":"b","c":"d"}`},
+	}
+
+	for _, test := range tests {
+		s := yamlJSON(t, test.yaml)
+		assert.True(s == test.json, fmt.Sprintf("%q: expected %s, got %s", test.yaml, test.json, s))
+	}
+}
+
+func TestYAMLDocuments(t *testing.T) {
+	assert := NewAssert(t)
+
+	values, err := NewValuesFromYAML(strings.NewReader(`apiVersion: v1
+kind: Service
+---
+# deployment
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: 3
+---
+`))
+	assert.True(err == nil, "failed to parse stream")
+	assert.True(len(values) == 3, "expected 3 documents")
+
+	o, _ := values[1].Object()
+	replicas, err := o.GetInt64("spec", "replicas")
+	assert.True(err == nil && replicas == 3, "expected 3 replicas")
+
+	assert.True(values[2].Null() == nil, "empty documents should be null")
+
+	_, err = NewValueFromYAML(strings.NewReader("a: 1\n---\nb: 2\n"))
+	assert.True(err != nil, "NewValueFromYAML should reject streams with several documents")
+}
+
+func TestYAMLErrors(t *testing.T) {
+	assert := NewAssert(t)
+
+	tests := []struct {
+		yaml string
+		line int
+	}{
+		{"a: 1\nb: [1, 2\n", 2},
+		{"a: [1, , 2]\n", 1},
+		{"a: 1\na: 2\n", 2},
+		{"a: 1\n  b: 2\n", 2},
+		{"a: b: c\n", 1},
+		{"a:\n\t- b\n", 2},
+		{"a: 1\nb: *missing\n", 2},
+		{"a: \"unclosed\n", 1},
+		{"a: \"\\q\"\n", 1},
+		{"a:\n  b: 1\n c: 2\n", 3},
+		{"x: .inf\n", 1},
+		{"? complex\n: key\n", 1},
+		{"[a, b]: c\n", 1},
+		{"a: !!int x\n", 1},
+		{"a: !!map 1\n", 1},
+		{"- a\nb: c\n", 2},
+	}
+
+	for _, test := range tests {
+		_, err := NewValueFromYAML(strings.NewReader(test.yaml))
+
+		var yamlErr *YAMLError
+		assert.True(errors.As(err, &yamlErr), fmt.Sprintf("%q: expected a YAMLError, got %v", test.yaml, err))
+		assert.True(yamlErr.Line == test.line, fmt.Sprintf("%q: expected an error on line %d, got %v", test.yaml, test.line, err))
+	}
+}
+
+func TestYAMLAliasLimit(t *testing.T) {
+	assert := NewAssert(t)
+
+	yaml := `a: &a [x, x, x, x, x, x, x, x, x, x]
+b: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a, *a]
+c: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b, *b]
+d: &d [*c, *c, *c, *c, *c, *c, *c, *c, *c, *c]
+e: &e [*d, *d, *d, *d, *d, *d, *d, *d, *d, *d]
+f: &f [*e, *e, *e, *e, *e, *e, *e, *e, *e, *e]
+`
+	_, err := NewValueFromYAML(strings.NewReader(yaml))
+
+	var yamlErr *YAMLError
+	assert.True(errors.As(err, &yamlErr) && yamlErr.Line == 6, "the alias limit should be hit on line 6")
+}
+
+func TestYAMLDepthLimit(t *testing.T) {
+	assert := NewAssert(t)
+
+	for _, yaml := range []string{
+		strings.Repeat("[", 4<<20),
+		strings.Repeat("{a: ", 20000),
+		strings.Repeat("- ", 20000) + "x\n",
+		"a: " + strings.Repeat("[", 20000),
+	} {
+		_, err := NewValueFromYAML(strings.NewReader(yaml))
+
+		var yamlErr *YAMLError
+		assert.True(errors.As(err, &yamlErr) && strings.Contains(yamlErr.Message, "exceeded max depth"), fmt.Sprintf("deep nesting should return a YAMLError, got %v", err))
+	}
+
+	s := yamlJSON(t, strings.Repeat("- ", 100)+strings.Repeat("[", 100)+strings.Repeat("]", 100)+"\n")
+	assert.True(s == strings.Repeat("[", 200)+strings.Repeat("]", 200), "nesting below the limit should parse")
+}